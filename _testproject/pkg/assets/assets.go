@@ -0,0 +1,31 @@
+// Package assets contains embedded files.
+package assets
+
+import (
+	"embed"
+	e "embed"
+)
+
+// Version is embedded as a string.
+//
+//go:embed version.txt
+var Version string
+
+// Logo is embedded as a byte slice.
+//
+//go:embed static/logo.svg
+var Logo []byte
+
+// Static is an embedded file system.
+//
+//go:embed static
+//go:embed "version.txt"
+var Static embed.FS
+
+// All includes hidden files.
+//
+//go:embed all:static
+var All e.FS
+
+// NotEmbedded is a plain variable.
+var NotEmbedded string
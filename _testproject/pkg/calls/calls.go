@@ -0,0 +1,45 @@
+// Package calls contains call graph test declarations.
+package calls
+
+import "math"
+
+// Shape is implemented by Square and Circle.
+type Shape interface {
+	Area() float64
+}
+
+// Square is a Shape with a value receiver.
+type Square struct {
+	Side float64
+}
+
+// Area implements Shape.
+func (s Square) Area() float64 { return s.Side * s.Side }
+
+// NewSquare returns a new *Square.
+func NewSquare(side float64) *Square { return &Square{Side: side} }
+
+// TotalArea calls Area through the Shape interface.
+func TotalArea(shapes ...Shape) (total float64) {
+	for _, s := range shapes {
+		total += s.Area()
+	}
+	return
+}
+
+// Apply calls a func value.
+func Apply(f func() float64) float64 { return f() }
+
+// Run calls funcs statically and passes a method value to Apply.
+func Run() float64 {
+	var sq = Square{Side: 2}
+	return TotalArea(sq, &Circle{R: 1}) + Apply(sq.Area) + float64(helper())
+}
+
+func helper() int {
+	var n = len("helper")
+	return n + int(math.Round(NewSquare(1).Area()))
+}
+
+// Unused is not called.
+func Unused() { helper() }
@@ -0,0 +1,11 @@
+package calls
+
+import "math"
+
+// Circle is a Shape with a pointer receiver.
+type Circle struct {
+	R float64
+}
+
+// Area implements Shape.
+func (c *Circle) Area() float64 { return math.Pi * c.R * c.R }
@@ -0,0 +1,10 @@
+package embedded
+
+// Car is a Vehicle.
+type Car struct{}
+
+// Name implements Namer.
+func (Car) Name() string { return "car" }
+
+// Drive implements Vehicle.
+func (Car) Drive() int { return 1 }
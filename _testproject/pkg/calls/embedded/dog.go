@@ -0,0 +1,10 @@
+package embedded
+
+// Dog is an Animal.
+type Dog struct{}
+
+// Name implements Namer.
+func (Dog) Name() string { return "dog" }
+
+// Speak implements Animal.
+func (Dog) Speak() string { return "woof" }
@@ -0,0 +1,25 @@
+// Package embedded contains interface calls of a method inherited from an
+// embedded interface.
+package embedded
+
+// Namer has a name.
+type Namer interface {
+	Name() string
+}
+
+// Animal is a Namer that speaks.
+type Animal interface {
+	Namer
+	Speak() string
+}
+
+// Vehicle is a Namer that drives.
+type Vehicle interface {
+	Namer
+	Drive() int
+}
+
+// Names calls Namer.Name through an Animal and a Vehicle.
+func Names(a Animal, v Vehicle) string {
+	return a.Name() + v.Name()
+}
@@ -0,0 +1,73 @@
+// Package ctors contains constructor test declarations.
+package ctors
+
+import (
+	"errors"
+	"io"
+)
+
+// User is constructed by NewUser, NewUserWithGroup and LoadUser.
+type User struct {
+	Name string
+}
+
+// Group is constructed by NewGroup.
+type Group struct {
+	Users []*User
+}
+
+// Store is constructed by NewStore.
+type Store interface {
+	Get(name string) *User
+}
+
+// ID is constructed by ParseID.
+type ID int
+
+// Handler is a func type.
+type Handler func() error
+
+// List is a generic type constructed by MakeList.
+type List[T any] struct {
+	Items []T
+}
+
+type user struct{}
+
+// NewUser returns a new *User.
+func NewUser(name string) *User { return &User{Name: name} }
+
+// NewUserWithGroup returns more than one local type and is associated by
+// name.
+func NewUserWithGroup(name string, g *Group) (*User, *Group, error) {
+	return NewUser(name), g, nil
+}
+
+// LoadUser is associated by return type.
+func LoadUser(r io.Reader) (User, error) { return User{}, errors.New("not implemented") }
+
+// NewGroup returns a Group value.
+func NewGroup() Group { return Group{} }
+
+// Pair returns more than one local type and is not a constructor.
+func Pair() (*User, *Group) { return nil, nil }
+
+// NewStore returns an interface.
+func NewStore() Store { return nil }
+
+// ParseID constructs a non-struct type.
+func ParseID(s string) (ID, error) { return 0, nil }
+
+func newUser() *user { return &user{} }
+
+// NewReader returns a type of another package.
+func NewReader() io.Reader { return nil }
+
+// Users returns a slice and is not a constructor.
+func Users() []*User { return nil }
+
+// NewHandler returns a func type.
+func NewHandler() Handler { return nil }
+
+// MakeList constructs a generic type.
+func MakeList[T any]() *List[T] { return &List[T]{} }
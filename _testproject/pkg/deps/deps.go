@@ -0,0 +1,81 @@
+// Package deps contains type dependency test declarations.
+package deps
+
+import "github.com/vedranvuk/bast/_testproject/pkg/types"
+
+// Status is a customer status.
+type Status int8
+
+// Country is referenced by Address.
+type Country struct {
+	Code string
+}
+
+// Address depends on Country.
+type Address struct {
+	Street  string
+	Country Country
+}
+
+// Base is embedded by Customer.
+type Base struct {
+	ID types.ID
+}
+
+// Tag is a map value type.
+type Tag string
+
+// Customer depends on Base, Status, Address and Tag.
+type Customer struct {
+	Base
+	Name      string
+	Status    Status
+	Addresses []*Address
+	Tags      map[string]Tag
+}
+
+// Keyed is a type constraint.
+type Keyed interface {
+	Key() Tag
+}
+
+// Set depends on Keyed through its type parameter.
+type Set[T Keyed] struct {
+	Items map[string]T
+}
+
+// Repository depends on Customer and embeds Keyed.
+type Repository interface {
+	Find(id types.ID) (*Customer, error)
+	Keyed
+}
+
+// Handler is a func type depending on Customer.
+type Handler func(Customer) error
+
+// DefaultCountry is a var of type Country.
+var DefaultCountry Country
+
+// NewCustomer depends on Address and Customer.
+func NewCustomer(name string, addr Address) *Customer {
+	return &Customer{Name: name, Addresses: []*Address{&addr}}
+}
+
+// Valid depends on its receiver type Customer.
+func (c *Customer) Valid() bool { return c.Name != "" }
+
+// Employee and Department are mutually recursive.
+type Employee struct {
+	Name       string
+	Department *Department
+}
+
+// Department and Employee are mutually recursive.
+type Department struct {
+	Name  string
+	Head  *Employee
+	Staff []Employee
+}
+
+// Tree is a recursive non-struct type.
+type Tree map[string]Tree
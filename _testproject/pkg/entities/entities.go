@@ -0,0 +1,43 @@
+// Package entities contains query test declarations.
+package entities
+
+// Entity is implemented by database entities.
+type Entity interface {
+	TableName() string
+}
+
+// User is a database entity.
+//
+//bast:entity users
+type User struct {
+	ID   int    `db:"id" json:"id"`
+	Name string `db:"name" json:"name"`
+}
+
+// Order is a database entity implemented by pointer.
+//
+//bast:entity
+type Order struct {
+	ID    int `db:"id"`
+	Total float64
+}
+
+// Draft has db tags but is not an entity.
+type Draft struct {
+	Body string `db:"body"`
+}
+
+// session is unexported.
+//
+//bast:entity
+type session struct {
+	Token string `json:"token"`
+}
+
+// Status is not a struct.
+//
+//bast:entityless
+type Status int
+
+// NewUser returns a new User.
+func NewUser() *User { return &User{} }
@@ -0,0 +1,4 @@
+package entities
+
+// TableName implements Entity.
+func (self User) TableName() string { return "users" }
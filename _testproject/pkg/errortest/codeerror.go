@@ -0,0 +1,8 @@
+package errortest
+
+import "fmt"
+
+// CodeError is a named error code.
+type CodeError int
+
+func (e CodeError) Error() string { return fmt.Sprintf("error %d", int(e)) }
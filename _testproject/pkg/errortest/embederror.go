@@ -0,0 +1,7 @@
+package errortest
+
+// EmbedError implements error by the Error method promoted from ValueError.
+type EmbedError struct {
+	ValueError
+	Detail string
+}
@@ -0,0 +1,19 @@
+package errortest
+
+import (
+	"errors"
+	stderrors "errors"
+	"fmt"
+)
+
+// Sentinel errors.
+var (
+	// ErrNotFound is returned when an item is not found.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalid is returned for invalid input.
+	ErrInvalid = fmt.Errorf("invalid input: %w", ErrNotFound)
+	// ErrAliased is declared using an aliased import.
+	ErrAliased = stderrors.New("aliased")
+	// NotSentinel is not an error.
+	NotSentinel = fmt.Sprintf("%d", 42)
+)
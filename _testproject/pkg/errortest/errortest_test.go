@@ -0,0 +1,6 @@
+package errortest
+
+// testError implements error in an in-package test file.
+type testError struct{}
+
+func (testError) Error() string { return "test" }
@@ -0,0 +1,6 @@
+package errortest_test
+
+// xErr implements error by pointer in an external test package.
+type xErr struct{}
+
+func (*xErr) Error() string { return "x" }
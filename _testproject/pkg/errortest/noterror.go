@@ -0,0 +1,6 @@
+package errortest
+
+// NotError has an Error method with a non-error signature.
+type NotError struct{}
+
+func (e NotError) Error(code int) string { return "" }
@@ -0,0 +1,10 @@
+package errortest
+
+import "fmt"
+
+// ValueError implements error by value.
+type ValueError struct {
+	Code int
+}
+
+func (e ValueError) Error() string { return fmt.Sprintf("code %d", e.Code) }
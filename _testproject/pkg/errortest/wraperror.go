@@ -0,0 +1,15 @@
+package errortest
+
+// WrapError implements error by pointer and supports unwrapping.
+type WrapError struct {
+	Op  string
+	Err error
+}
+
+func (e *WrapError) Error() string { return e.Op + ": " + e.Err.Error() }
+
+func (e *WrapError) Unwrap() error { return e.Err }
+
+func (e *WrapError) Is(target error) bool { return target == ErrNotFound }
+
+func (e *WrapError) As(target any) bool { return false }
@@ -0,0 +1,8 @@
+// Package exampletest contains examples of declarations shadowed by methods.
+package exampletest
+
+// Shadow has a method named like the Render func.
+type Shadow struct{}
+
+// Render renders the shadow.
+func (Shadow) Render() string { return "shadow" }
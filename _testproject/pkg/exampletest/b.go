@@ -0,0 +1,4 @@
+package exampletest
+
+// Render renders the package.
+func Render() string { return "package" }
@@ -0,0 +1,17 @@
+package exampletest_test
+
+import (
+	"fmt"
+
+	"github.com/vedranvuk/bast/_testproject/pkg/exampletest"
+)
+
+func ExampleRender() {
+	fmt.Println(exampletest.Render())
+	// Output: package
+}
+
+func ExampleShadow_Render() {
+	fmt.Println(exampletest.Shadow{}.Render())
+	// Output: shadow
+}
@@ -0,0 +1,17 @@
+package exampletest_test
+
+import (
+	"fmt"
+
+	"github.com/vedranvuk/bast/_testproject/pkg/exampletest"
+)
+
+// twice renders r two times.
+func twice(r interface{ Render() string }) string {
+	return r.Render() + " " + r.Render()
+}
+
+func ExampleShadow() {
+	fmt.Println(twice(exampletest.Shadow{}))
+	// Output: shadow shadow
+}
@@ -269,4 +269,14 @@ var (
 	NodeTree          = Node[string]{Value: "root", Children: []*Node[string]{{Value: "child"}}}
 	OrderedContainer  = Container[int, string]{Key: 1, Value: "test"}
 	ProcessorInstance = Reader[types.ID]{data: types.ID(42)}
-)
\ No newline at end of file
+)
+
+// Shadow has type parameters shadowing the Pair type and the context import.
+func Shadow[Pair any, context any](p Pair, c context) Pair {
+	return p
+}
+
+// Rename names the Node type parameter Pair, shadowing the Pair type.
+func (n *Node[Pair]) Rename(value Pair) Pair {
+	return value
+}
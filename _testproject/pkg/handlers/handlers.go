@@ -0,0 +1,50 @@
+// Package handlers contains signature matching test declarations.
+package handlers
+
+import (
+	"context"
+	"net/http"
+)
+
+// User is a command payload.
+type User struct {
+	Name string
+}
+
+// Group is a command result.
+type Group struct {
+	Users []*User
+}
+
+// Service is a gRPC-like service.
+type Service interface {
+	// GetUser is a service method.
+	GetUser(ctx context.Context, req *User) (*Group, error)
+}
+
+// ServeIndex is an HTTP handler.
+func ServeIndex(w http.ResponseWriter, r *http.Request) {}
+
+// ServeUser is an HTTP handler.
+func ServeUser(w http.ResponseWriter, r *http.Request) {}
+
+// CreateUser is a command handler.
+func CreateUser(ctx context.Context, user *User) (*User, error) { return user, nil }
+
+// AddUser is a command handler.
+func AddUser(ctx context.Context, user *User) (*Group, error) { return nil, nil }
+
+// Value returns a non-pointer result.
+func Value(ctx context.Context, user *User) (User, error) { return *user, nil }
+
+// Join is variadic.
+func Join(sep string, users ...*User) string { return sep }
+
+// Server implements Service.
+type Server struct{}
+
+// GetUser is a service method.
+func (self *Server) GetUser(ctx context.Context, req *User) (*Group, error) { return nil, nil }
+
+// HandlerFunc is a func type, never matched.
+type HandlerFunc func(ctx context.Context, user *User) (*User, error)
@@ -0,0 +1,40 @@
+package models_test
+
+import (
+	"fmt"
+
+	"github.com/vedranvuk/bast/_testproject/pkg/models"
+)
+
+func Example() {
+	fmt.Println("models")
+	// Output: models
+}
+
+func ExampleTestFunc3() {
+	fmt.Println(models.TestFunc3())
+	// Output: 0 <nil>
+}
+
+func ExampleTestStruct1_TestMethod1() {
+	models.TestStruct1{}.TestMethod1()
+}
+
+func ExampleTestStruct2_second() {
+	var s models.TestStruct2
+	fmt.Println(s.FooField == "")
+	// Output: true
+}
+
+func ExampleInterface2_IntfMethod1() {}
+
+func ExampleTestFunc6() {
+	a, b, c := models.TestFunc6()
+	for _, v := range []int{c, b, a} {
+		fmt.Println(v)
+	}
+	// Unordered output:
+	// 0
+	// 1
+	// 2
+}
@@ -0,0 +1,21 @@
+package models
+
+import "testing"
+
+// TestTestFunc1 is a test.
+func TestTestFunc1(t *testing.T) { TestFunc1() }
+
+// BenchmarkTestFunc2 is a benchmark.
+func BenchmarkTestFunc2(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_ = TestFunc2()
+	}
+}
+
+// FuzzTestFunc7 is a fuzz target.
+func FuzzTestFunc7(f *testing.F) {
+	f.Fuzz(func(t *testing.T, in int) { _ = TestFunc7[int](in) })
+}
+
+// helper is not a test.
+func helper() {}
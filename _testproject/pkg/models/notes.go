@@ -0,0 +1,29 @@
+package models
+
+// TODO(vedran): Move notes into their own package.
+
+// DeprecatedFunc is a deprecated func.
+//
+// Deprecated: Use TestFunc1 instead.
+// It will be removed.
+//
+// This paragraph is not a part of the deprecation notice.
+func DeprecatedFunc() {
+	// FIXME: this does nothing
+	// and spans two lines.
+}
+
+// NotedStruct is a struct with notes.
+type NotedStruct struct {
+	// Field is a deprecated field.
+	//
+	// Deprecated: Do not use.
+	Field string
+	// BUG(vedran): Other has no purpose.
+	Other int
+}
+
+/*
+HACK: a custom note marker in a block comment.
+*/
+var NotedVar = 0
@@ -0,0 +1,20 @@
+// Package notetest contains notes inside methods sharing a name.
+package notetest
+
+// A is a type with a String method.
+type A struct{}
+
+// B is a type with a String method.
+type B struct{}
+
+// String returns the name of A.
+func (A) String() string {
+	// TODO: A note inside A.String.
+	return "A"
+}
+
+// String returns the name of B.
+func (B) String() string {
+	// TODO: A note inside B.String.
+	return "B"
+}
@@ -0,0 +1,57 @@
+// Package resolve contains type declarations for type resolution tests.
+package resolve
+
+import (
+	"time"
+
+	ids "github.com/vedranvuk/bast/_testproject/pkg/types"
+)
+
+// Size is an array length.
+const Size = 4
+
+// Base is a basic type.
+type Base int
+
+// Level is defined by Base.
+type Level Base
+
+// Priority is defined by Level.
+type Priority Level
+
+// Alias is an alias of Priority.
+type Alias = Priority
+
+// IDs is a slice of a type from another package.
+type IDs []ids.ID
+
+// Index is a map of pointers.
+type Index map[string]*Level
+
+// Timeout is defined by a standard library type.
+type Timeout time.Duration
+
+// Buffer is an array with a constant length.
+type Buffer [Size]byte
+
+// Handler is a func type.
+type Handler func(name string, args ...int) error
+
+// Events is a send only channel.
+type Events chan<- Priority
+
+// List is a recursive slice.
+type List []List
+
+// Pair is a generic struct.
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Container is a generic struct using its type parameter.
+type Container[T any] struct {
+	Items []T
+	Pairs map[string]Pair[string, T]
+	Owner *Pair[Level, ids.ID]
+}
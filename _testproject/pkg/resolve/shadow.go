@@ -0,0 +1,18 @@
+package resolve
+
+// Box has a field named like the Get func.
+type Box[T any] struct {
+	Get T
+}
+
+// Getter has a method named like the Get func.
+type Getter[T any] interface {
+	Get() T
+}
+
+// Get shadows the names of the Box field, the Getter method and the Put
+// parameter.
+func Get() {}
+
+// Put has a parameter named like the Get func.
+func (b *Box[T]) Put(Get T) {}
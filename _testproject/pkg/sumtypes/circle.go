@@ -0,0 +1,16 @@
+package sumtypes
+
+// Circle is a Shape variant with value receivers.
+type Circle struct {
+	R float64
+}
+
+func (Circle) isShape() {}
+
+// Area implements Shape.
+func (c Circle) Area() float64 { return 3 * c.R * c.R }
+
+// Start is an Event variant.
+type Start struct{}
+
+func (Start) isEvent() {}
@@ -0,0 +1,14 @@
+package sumtypes
+
+// Cube is a Shape and a Solid variant.
+type Cube struct {
+	A float64
+}
+
+func (*Cube) isShape() {}
+
+// Area implements Shape.
+func (c *Cube) Area() float64 { return 6 * c.A * c.A }
+
+// Volume implements Solid.
+func (c *Cube) Volume() float64 { return c.A * c.A * c.A }
@@ -0,0 +1,16 @@
+package sumtypes
+
+// Square is a Shape variant with pointer receivers.
+type Square struct {
+	S float64
+}
+
+func (*Square) isShape() {}
+
+// Area implements Shape.
+func (s *Square) Area() float64 { return s.S * s.S }
+
+// Stop is an Event variant.
+type Stop struct{}
+
+func (Stop) isEvent() {}
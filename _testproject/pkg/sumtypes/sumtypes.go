@@ -0,0 +1,81 @@
+// Package sumtypes contains sealed interface test declarations.
+package sumtypes
+
+// Shape is a sealed interface implemented by Circle, Square, Triangle and
+// Cube.
+type Shape interface {
+	isShape()
+	Area() float64
+}
+
+// Solid is sealed through embedding of Shape.
+type Solid interface {
+	Shape
+	Volume() float64
+}
+
+// Event is a sealed interface implemented by Start and Stop.
+type Event interface {
+	isEvent()
+}
+
+// Open is not sealed.
+type Open interface {
+	Area() float64
+}
+
+// Describe switches over all Shape variants.
+func Describe(s Shape) string {
+	switch s.(type) {
+	case Circle:
+		return "circle"
+	case *Square:
+		return "square"
+	case Triangle, *Cube:
+		return "other"
+	}
+	return ""
+}
+
+// Partial misses Triangle and Cube variants.
+func Partial(s Shape) string {
+	switch v := s.(type) {
+	case Circle:
+		return "circle"
+	case *Square:
+		_ = v
+		return "square"
+	}
+	return ""
+}
+
+// WithDefault misses variants but has a default clause.
+func WithDefault(s Shape) string {
+	switch s.(type) {
+	case Circle:
+		return "circle"
+	default:
+		return "other"
+	}
+}
+
+// EventName misses the Stop variant and covers Cube through Solid.
+func EventName(e Event, s Shape) string {
+	switch s.(type) {
+	case Solid, Circle, *Square, Triangle:
+	}
+	switch e.(type) {
+	case Start:
+		return "start"
+	}
+	return ""
+}
+
+// NotSum switches over a non sum type.
+func NotSum(v any) string {
+	switch v.(type) {
+	case int:
+		return "int"
+	}
+	return ""
+}
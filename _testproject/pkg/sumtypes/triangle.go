@@ -0,0 +1,11 @@
+package sumtypes
+
+// Triangle is a Shape variant.
+type Triangle struct {
+	B, H float64
+}
+
+func (Triangle) isShape() {}
+
+// Area implements Shape.
+func (t Triangle) Area() float64 { return t.B * t.H / 2 }
@@ -0,0 +1,37 @@
+package cgotest
+
+/*
+#cgo CFLAGS: -DCGOTEST=1 -O2
+#cgo linux LDFLAGS: -lm
+#include <stdlib.h>
+#include <math.h>
+
+typedef struct {
+	int x;
+	int y;
+} point;
+
+static int add(int a, int b) { return a + b; }
+*/
+import "C"
+
+import "unsafe"
+
+// Point is a Go wrapper of a C struct.
+type Point struct {
+	p C.point
+}
+
+// Add adds two numbers in C.
+func Add(a, b int) int {
+	return int(C.add(C.int(a), C.int(b)))
+}
+
+// Sqrt returns a square root computed in C.
+func Sqrt(x float64) float64 { return float64(C.sqrt(C.double(x))) }
+
+// Free frees p.
+func Free(p unsafe.Pointer) { C.free(p) }
+
+// NoCgo does not reference C.
+func NoCgo() int { return 0 }
@@ -0,0 +1,2 @@
+// Package cgotest contains cgo code.
+package cgotest
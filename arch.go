@@ -0,0 +1,230 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"fmt"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// ArchRules are architecture rules for package imports, checked by
+// [Bast.CheckArchitecture].
+//
+// Package patterns follow go list syntax where "..." matches any string,
+// e.g. "pkg/domain/..." matches "pkg/domain" and all packages below it.
+// Patterns match full import paths or import paths relative to the module
+// of the importing package.
+//
+// Rules are usually declared in JSON, e.g.:
+//
+//	{
+//		"rules": [
+//			{"name": "domain", "packages": ["pkg/domain/..."], "deny": ["pkg/infra/..."]},
+//			{"name": "wire", "deny": ["internal/wire"], "except": ["cmd/..."]}
+//		],
+//		"layers": [
+//			{"name": "cmd", "packages": ["cmd/..."]},
+//			{"name": "app", "packages": ["pkg/app/..."]},
+//			{"name": "domain", "packages": ["pkg/domain/..."]}
+//		]
+//	}
+type ArchRules struct {
+	// Rules are the import rules.
+	Rules []*ArchRule `json:"rules,omitempty"`
+	// Layers are package layers ordered from the outermost to the innermost.
+	//
+	// A package in a layer may import packages from its own and inner
+	// layers but not from outer layers. Packages not in any layer are not
+	// constrained. A package belongs to the first layer it matches.
+	Layers []*ArchLayer `json:"layers,omitempty"`
+}
+
+// ArchRule denies imports of packages matching Deny patterns to packages
+// matching Packages patterns, except packages matching Except patterns.
+type ArchRule struct {
+	// Name is the rule name reported in violations.
+	//
+	// If empty, "rules[i]" is used where i is the rule index.
+	Name string `json:"name,omitempty"`
+	// Packages are patterns of packages the rule applies to.
+	//
+	// If empty, the rule applies to all packages.
+	Packages []string `json:"packages,omitempty"`
+	// Except are patterns of packages the rule does not apply to.
+	Except []string `json:"except,omitempty"`
+	// Deny are patterns of packages that must not be imported.
+	Deny []string `json:"deny,omitempty"`
+}
+
+// ArchLayer is a named group of packages.
+type ArchLayer struct {
+	// Name is the layer name reported in violations.
+	Name string `json:"name"`
+	// Packages are patterns of packages in the layer.
+	Packages []string `json:"packages"`
+}
+
+// ArchViolation is an import violating an architecture rule.
+type ArchViolation struct {
+	// Rule is the name of the violated rule or "layers" for layer order
+	// violations.
+	Rule string
+	// Reason describes the violation.
+	Reason string
+	// Package is the import path of the importing package.
+	Package string
+	// Import is the offending import path.
+	Import string
+	// Pos is the position of the offending import spec.
+	Pos token.Position
+}
+
+// String implements fmt.Stringer.
+func (self *ArchViolation) String() string {
+	return fmt.Sprintf("%s: %s imports %s: %s: %s", self.Pos, self.Package, self.Import, self.Rule, self.Reason)
+}
+
+// CheckArchitecture checks imports of all loaded packages against rules
+// and returns violations in load order of packages and files.
+//
+// Each import is reported once per violated rule. Test packages are not
+// checked. If rules is nil there are no violations. Nil rules and layers,
+// e.g. null entries in JSON, are ignored.
+func (self *Bast) CheckArchitecture(rules *ArchRules) (out []*ArchViolation) {
+
+	if rules == nil {
+		return nil
+	}
+
+	type rule struct {
+		name                   string
+		packages, except, deny packagePatterns
+	}
+	type layer struct {
+		name     string
+		packages packagePatterns
+	}
+
+	var (
+		checks = make([]rule, 0, len(rules.Rules))
+		layers = make([]layer, 0, len(rules.Layers))
+	)
+	for i, r := range rules.Rules {
+		if r == nil {
+			continue
+		}
+		var name = r.Name
+		if name == "" {
+			name = fmt.Sprintf("rules[%d]", i)
+		}
+		checks = append(checks, rule{
+			name:     name,
+			packages: compilePackagePatterns(r.Packages),
+			except:   compilePackagePatterns(r.Except),
+			deny:     compilePackagePatterns(r.Deny),
+		})
+	}
+	for _, l := range rules.Layers {
+		if l == nil {
+			continue
+		}
+		layers = append(layers, layer{l.Name, compilePackagePatterns(l.Packages)})
+	}
+	var layerOf = func(pkgPath, module string) int {
+		for i, l := range layers {
+			if l.packages.match(pkgPath, module) >= 0 {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for _, pkg := range self.packages.Values() {
+		if pkg.TestVariantOf != "" {
+			continue
+		}
+		var module string
+		if pkg.pkg != nil && pkg.pkg.Module != nil {
+			module = pkg.pkg.Module.Path
+		}
+		var (
+			applies   = make([]bool, len(checks))
+			fromLayer = layerOf(pkg.Path, module)
+		)
+		for i, c := range checks {
+			applies[i] = (len(c.packages) == 0 || c.packages.match(pkg.Path, module) >= 0) &&
+				c.except.match(pkg.Path, module) < 0
+		}
+		for _, file := range pkg.Files.Values() {
+			for _, imp := range file.Imports.Values() {
+				var violation = func(rule, reason string) {
+					out = append(out, &ArchViolation{
+						Rule:    rule,
+						Reason:  reason,
+						Package: pkg.Path,
+						Import:  imp.Path,
+						Pos:     imp.Pos,
+					})
+				}
+				for i, c := range checks {
+					if !applies[i] {
+						continue
+					}
+					if idx := c.deny.match(imp.Path, module); idx >= 0 {
+						violation(c.name, fmt.Sprintf("import of %s is denied", c.deny[idx].pattern))
+					}
+				}
+				if fromLayer < 0 {
+					continue
+				}
+				if toLayer := layerOf(imp.Path, module); toLayer >= 0 && toLayer < fromLayer {
+					violation("layers", fmt.Sprintf("layer %s must not import outer layer %s",
+						layers[fromLayer].name, layers[toLayer].name))
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// packagePattern is a compiled package pattern.
+type packagePattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// packagePatterns is a list of compiled package patterns.
+type packagePatterns []*packagePattern
+
+// compilePackagePatterns compiles go list style package patterns.
+func compilePackagePatterns(patterns []string) (out packagePatterns) {
+	for _, pattern := range patterns {
+		var re = strings.ReplaceAll(regexp.QuoteMeta(pattern), `\.\.\.`, `.*`)
+		// As with go list, "x/..." also matches "x".
+		if trimmed, ok := strings.CutSuffix(re, `/.*`); ok {
+			re = trimmed + `(/.*)?`
+		}
+		out = append(out, &packagePattern{pattern, regexp.MustCompile(`^` + re + `$`)})
+	}
+	return
+}
+
+// match returns the index of the first pattern matching import path
+// pkgPath, either in full or relative to module, or -1 if none match.
+func (self packagePatterns) match(pkgPath, module string) int {
+	var rel, inModule = "", false
+	if module != "" {
+		rel, inModule = strings.CutPrefix(pkgPath, module+"/")
+	}
+	for i, p := range self {
+		if p.re.MatchString(pkgPath) || (inModule && p.re.MatchString(rel)) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,139 @@
+package bast
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCheckArchitecture tests architecture rule checking
+func TestCheckArchitecture(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	const prefix = "github.com/vedranvuk/bast/_testproject/pkg/"
+
+	check := func(t *testing.T, config string) []*ArchViolation {
+		var rules = &ArchRules{}
+		if err := json.Unmarshal([]byte(config), rules); err != nil {
+			t.Fatal(err)
+		}
+		return bast.CheckArchitecture(rules)
+	}
+
+	t.Run("NilRules", func(t *testing.T) {
+		if violations := bast.CheckArchitecture(nil); violations != nil {
+			t.Errorf("Expected no violations for nil rules, got %v", violations)
+		}
+	})
+
+	t.Run("NilEntries", func(t *testing.T) {
+		violations := check(t, `{
+			"rules": [null, {"packages": ["pkg/models"], "deny": ["pkg/types/..."]}],
+			"layers": [null]
+		}`)
+		if len(violations) != 1 || violations[0].Rule != "rules[1]" {
+			t.Errorf("Expected a single rules[1] violation, got %v", violations)
+		}
+	})
+
+	t.Run("Deny", func(t *testing.T) {
+		violations := check(t, `{"rules": [
+			{"name": "models", "packages": ["pkg/models"], "deny": ["pkg/types/..."]},
+			{"packages": ["pkg/edgecases", "pkg/errortest"], "deny": ["unsafe"]}
+		]}`)
+		if len(violations) != 3 {
+			t.Fatalf("Expected 3 violations, got %v", violations)
+		}
+
+		var (
+			v      *ArchViolation
+			unsafe int
+		)
+		for _, violation := range violations {
+			switch violation.Rule {
+			case "models":
+				v = violation
+			case "rules[1]":
+				if violation.Import == "unsafe" {
+					unsafe++
+				}
+			}
+		}
+		if unsafe != 2 {
+			t.Errorf("Expected 2 unsafe import violations, got %d", unsafe)
+		}
+		if v == nil {
+			t.Fatal("Expected a models rule violation")
+		}
+		if v.Package != prefix+"models" || v.Import != prefix+"types" {
+			t.Errorf("Unexpected violation: %s", v)
+		}
+		if filepath.Base(v.Pos.Filename) != "models.go" || v.Pos.Line == 0 {
+			t.Errorf("Expected position in models.go, got %s", v.Pos)
+		}
+		if v.Reason != "import of pkg/types/... is denied" {
+			t.Errorf("Unexpected reason: %s", v.Reason)
+		}
+		if !strings.HasPrefix(v.String(), v.Pos.String()+": ") {
+			t.Errorf("Unexpected violation string: %s", v)
+		}
+	})
+
+	t.Run("Except", func(t *testing.T) {
+		if violations := check(t, `{"rules": [
+			{"deny": ["pkg/generics"], "except": ["pkg/crosspkg"]}
+		]}`); len(violations) != 0 {
+			t.Errorf("Expected no violations, got %v", violations)
+		}
+		violations := check(t, `{"rules": [{"deny": ["pkg/generics"]}]}`)
+		if len(violations) != 1 || violations[0].Package != prefix+"crosspkg" {
+			t.Errorf("Expected a crosspkg violation, got %v", violations)
+		}
+	})
+
+	t.Run("Layers", func(t *testing.T) {
+		if violations := check(t, `{"layers": [
+			{"name": "app", "packages": ["pkg/crosspkg"]},
+			{"name": "domain", "packages": ["pkg/models", "pkg/generics"]},
+			{"name": "base", "packages": ["pkg/types"]}
+		]}`); len(violations) != 0 {
+			t.Errorf("Expected no violations, got %v", violations)
+		}
+		violations := check(t, `{"layers": [
+			{"name": "base", "packages": ["pkg/types"]},
+			{"name": "domain", "packages": ["pkg/models"]}
+		]}`)
+		if len(violations) != 1 {
+			t.Fatalf("Expected 1 violation, got %v", violations)
+		}
+		if v := violations[0]; v.Rule != "layers" || v.Package != prefix+"models" ||
+			v.Reason != "layer domain must not import outer layer base" {
+			t.Errorf("Unexpected violation: %s", v)
+		}
+	})
+}
+
+// TestPackagePatterns tests go list style package pattern matching
+func TestPackagePatterns(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		expected      bool
+	}{
+		{"fmt", "fmt", true},
+		{"fmt", "fmt/x", false},
+		{"net/...", "net", true},
+		{"net/...", "net/http", true},
+		{"net/...", "network", false},
+		{"pkg/domain/...", "example.com/mod/pkg/domain/user", true},
+		{"pkg/domain/...", "example.com/other/pkg/domain/user", false},
+		{"example.com/mod/...", "example.com/mod/pkg", true},
+		{"pkg/.../internal", "example.com/mod/pkg/a/b/internal", true},
+		{"pkg.v2", "pkgxv2", false},
+	}
+	for _, test := range tests {
+		if matched := compilePackagePatterns([]string{test.pattern}).match(test.path, "example.com/mod") >= 0; matched != test.expected {
+			t.Errorf("pattern %q, path %q: expected %v", test.pattern, test.path, test.expected)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -53,6 +54,34 @@ func runTestCases(t *testing.T, testCases []TestCase) {
 	}
 }
 
+// testProjects are loads of all _testproject packages without and with
+// tests, shared by tests that do not modify the model.
+var testProjects [2]struct {
+	once sync.Once
+	bast *Bast
+	err  error
+}
+
+// loadTestProject returns all _testproject packages loaded once per value
+// of Config.Tests. Tests must not modify the returned model.
+func loadTestProject(t testing.TB, tests bool) *Bast {
+	t.Helper()
+	var p = &testProjects[0]
+	if tests {
+		p = &testProjects[1]
+	}
+	p.once.Do(func() {
+		cfg := DefaultConfig()
+		cfg.Dir = "_testproject"
+		cfg.Tests = tests
+		p.bast, p.err = Load(cfg, "./...")
+	})
+	if p.err != nil {
+		t.Fatalf("Failed to load test project: %v", p.err)
+	}
+	return p.bast
+}
+
 // TestLoadAndParse tests the main Load functionality with various configurations
 func TestLoadAndParse(t *testing.T) {
 	testCases := []TestCase{
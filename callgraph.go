@@ -0,0 +1,368 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"io"
+	"strconv"
+)
+
+// CallKind specifies the kind of a [CallEdge].
+type CallKind int
+
+const (
+	// CallStatic is a call of a func or a method of a concrete type.
+	CallStatic CallKind = iota
+	// CallInterface is a call of an interface method, dispatched
+	// dynamically to one of the concrete targets.
+	CallInterface
+	// CallUnknown is a call of a func value whose target is unknown, e.g. a
+	// func parameter, variable, field or literal.
+	CallUnknown
+)
+
+// String implements fmt.Stringer.
+func (self CallKind) String() string {
+	switch self {
+	case CallStatic:
+		return "static"
+	case CallInterface:
+		return "interface"
+	case CallUnknown:
+		return "unknown"
+	}
+	return "invalid"
+}
+
+// CallEdge is a call site in a func or method body.
+type CallEdge struct {
+	// Caller is the calling *Func or *Method.
+	Caller Declaration
+	// Callee is the called *Func or *Method.
+	//
+	// For interface calls it is the interface method if the interface is
+	// declared in a loaded package, otherwise nil. For unknown calls it is
+	// always nil.
+	Callee Declaration
+	// Targets are the methods of concrete types from loaded packages that
+	// implement the interface of an interface call, in package load order
+	// and by type name within a package.
+	Targets []*Method
+	// Kind is the kind of call.
+	Kind CallKind
+	// Pos is the position of the call expression.
+	Pos token.Position
+	// Expr is the called expression as it appears in source, e.g.
+	// "s.Area" or "f".
+	Expr string
+}
+
+// CallGraph is a call graph of funcs and methods from loaded packages.
+//
+// Calls of funcs and methods declared outside of loaded packages are not
+// recorded, except for interface calls with targets in loaded packages.
+type CallGraph struct {
+	// Nodes are the funcs and methods with bodies, in load order.
+	Nodes []Declaration
+	// Edges are the calls from all nodes in order of appearance, with
+	// outer calls preceding calls in their arguments or receivers.
+	Edges []*CallEdge
+
+	callers map[Declaration][]*CallEdge
+	callees map[Declaration][]*CallEdge
+}
+
+// CallGraph builds a call graph from func and method bodies of all loaded
+// packages.
+//
+// Calls from func literals are attributed to the enclosing func or method.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Bast) CallGraph() *CallGraph {
+
+	var (
+		out = &CallGraph{
+			callers: make(map[Declaration][]*CallEdge),
+			callees: make(map[Declaration][]*CallEdge),
+		}
+		targets = make(map[targetKey][]*Method)
+	)
+
+	for _, pkg := range self.packages.Values() {
+		if pkg.pkg == nil || pkg.pkg.TypesInfo == nil {
+			continue
+		}
+		var info = pkg.pkg.TypesInfo
+		for _, file := range pkg.ownSyntax() {
+			for _, d := range file.Decls {
+				var fd, ok = d.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				var caller = self.declOf(info.Defs[fd.Name])
+				if caller == nil {
+					continue
+				}
+				out.Nodes = append(out.Nodes, caller)
+				ast.Inspect(fd.Body, func(n ast.Node) bool {
+					var call, ok = n.(*ast.CallExpr)
+					if !ok {
+						return true
+					}
+					var edge = self.callEdge(info, call, targets)
+					if edge == nil {
+						return true
+					}
+					edge.Caller = caller
+					edge.Pos = pkg.pkg.Fset.Position(call.Lparen)
+					out.add(edge)
+					return true
+				})
+			}
+		}
+	}
+
+	return out
+}
+
+// Callers returns the calls of fn, including interface calls that may
+// dispatch to fn, in order of appearance.
+func (self *CallGraph) Callers(fn Declaration) []*CallEdge { return self.callers[fn] }
+
+// Callees returns the calls made by fn in order of appearance.
+func (self *CallGraph) Callees(fn Declaration) []*CallEdge { return self.callees[fn] }
+
+// Reachable returns the funcs and methods reachable from funcs and methods
+// in from, including from, in breadth-first order.
+//
+// Interface calls are followed to all of their targets.
+func (self *CallGraph) Reachable(from ...Declaration) (out []Declaration) {
+	var (
+		seen  = make(map[Declaration]bool)
+		queue []Declaration
+		visit = func(decl Declaration) {
+			if decl != nil && !seen[decl] {
+				seen[decl] = true
+				queue = append(queue, decl)
+			}
+		}
+	)
+	for _, decl := range from {
+		visit(decl)
+	}
+	for len(queue) > 0 {
+		var decl = queue[0]
+		queue = queue[1:]
+		out = append(out, decl)
+		for _, edge := range self.callees[decl] {
+			visit(edge.Callee)
+			for _, m := range edge.Targets {
+				visit(m)
+			}
+		}
+	}
+	return
+}
+
+// WriteDOT writes the graph to w in Graphviz DOT format.
+//
+// Nodes are named by package qualified names. Interface calls are drawn as
+// dashed edges to each target and unknown calls are omitted.
+func (self *CallGraph) WriteDOT(w io.Writer) (err error) {
+	var p = func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	p("digraph callgraph {\n")
+	for _, node := range self.Nodes {
+		p("\t%s;\n", strconv.Quote(qualifiedName(node)))
+	}
+	for _, edge := range self.Edges {
+		var caller = strconv.Quote(qualifiedName(edge.Caller))
+		switch edge.Kind {
+		case CallStatic:
+			p("\t%s -> %s;\n", caller, strconv.Quote(qualifiedName(edge.Callee)))
+		case CallInterface:
+			for _, m := range edge.Targets {
+				p("\t%s -> %s [style=dashed];\n", caller, strconv.Quote(qualifiedName(m)))
+			}
+		}
+	}
+	p("}\n")
+	return
+}
+
+// MarshalJSON implements json.Marshaler.
+//
+// Declarations are encoded as package qualified names.
+func (self *CallGraph) MarshalJSON() ([]byte, error) {
+
+	type jsonEdge struct {
+		Caller  string   `json:"caller"`
+		Callee  string   `json:"callee,omitempty"`
+		Targets []string `json:"targets,omitempty"`
+		Kind    string   `json:"kind"`
+		Pos     string   `json:"pos"`
+		Expr    string   `json:"expr"`
+	}
+	var out struct {
+		Nodes []string   `json:"nodes"`
+		Edges []jsonEdge `json:"edges"`
+	}
+
+	out.Nodes = make([]string, 0, len(self.Nodes))
+	for _, node := range self.Nodes {
+		out.Nodes = append(out.Nodes, qualifiedName(node))
+	}
+	out.Edges = make([]jsonEdge, 0, len(self.Edges))
+	for _, edge := range self.Edges {
+		var e = jsonEdge{
+			Caller: qualifiedName(edge.Caller),
+			Kind:   edge.Kind.String(),
+			Pos:    edge.Pos.String(),
+			Expr:   edge.Expr,
+		}
+		if edge.Callee != nil {
+			e.Callee = qualifiedName(edge.Callee)
+		}
+		for _, m := range edge.Targets {
+			e.Targets = append(e.Targets, qualifiedName(m))
+		}
+		out.Edges = append(out.Edges, e)
+	}
+
+	return json.Marshal(out)
+}
+
+// add adds edge to the graph.
+func (self *CallGraph) add(edge *CallEdge) {
+	self.Edges = append(self.Edges, edge)
+	self.callees[edge.Caller] = append(self.callees[edge.Caller], edge)
+	if edge.Callee != nil {
+		self.callers[edge.Callee] = append(self.callers[edge.Callee], edge)
+	}
+	for _, m := range edge.Targets {
+		if Declaration(m) != edge.Callee {
+			self.callers[m] = append(self.callers[m], edge)
+		}
+	}
+}
+
+// targetKey is the key of cached concrete targets of interface method fn
+// called through interface type recv.
+//
+// A method of an embedded interface is the same func for all interfaces
+// embedding it, but their implementers differ.
+type targetKey struct {
+	recv string
+	fn   *types.Func
+}
+
+// callEdge returns the edge for call or nil if call is a conversion, a
+// builtin call or a call of a func declared outside of loaded packages.
+//
+// targets caches concrete targets of interface methods.
+func (self *Bast) callEdge(info *types.Info, call *ast.CallExpr, targets map[targetKey][]*Method) *CallEdge {
+
+	if tv, ok := info.Types[call.Fun]; ok && (tv.IsType() || tv.IsBuiltin()) {
+		return nil
+	}
+
+	var fun = ast.Unparen(call.Fun)
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+	var edge = &CallEdge{
+		Kind: CallUnknown,
+		Expr: types.ExprString(fun),
+	}
+
+	var id *ast.Ident
+	switch f := fun.(type) {
+	case *ast.Ident:
+		id = f
+	case *ast.SelectorExpr:
+		id = f.Sel
+		if sel, ok := info.Selections[f]; ok && sel.Kind() != types.FieldVal && types.IsInterface(sel.Recv()) {
+			var fn = sel.Obj().(*types.Func)
+			edge.Kind = CallInterface
+			edge.Callee = self.declOf(fn)
+			var key = targetKey{types.TypeString(sel.Recv(), nil), fn}
+			if _, cached := targets[key]; !cached {
+				targets[key] = self.interfaceTargets(sel.Recv(), fn)
+			}
+			edge.Targets = targets[key]
+			if edge.Callee == nil && len(edge.Targets) == 0 {
+				return nil
+			}
+			return edge
+		}
+	}
+	if id == nil {
+		return edge
+	}
+
+	var fn, ok = info.Uses[id].(*types.Func)
+	if !ok {
+		return edge
+	}
+	if edge.Callee = self.declOf(fn); edge.Callee == nil {
+		return nil
+	}
+	edge.Kind = CallStatic
+	return edge
+}
+
+// interfaceTargets returns methods named as interface method fn of
+// non-generic concrete types from loaded packages that implement
+// interface type recv.
+func (self *Bast) interfaceTargets(recv types.Type, fn *types.Func) (out []*Method) {
+
+	var intf, ok = recv.Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+
+	for _, pkg := range self.packages.Values() {
+		if pkg.pkg == nil || pkg.pkg.Types == nil {
+			continue
+		}
+		var scope = pkg.pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			var tn, ok = scope.Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() {
+				continue
+			}
+			var named, _ = tn.Type().(*types.Named)
+			if named == nil || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+				continue
+			}
+			var typ types.Type = named
+			if !types.Implements(typ, intf) {
+				if typ = types.NewPointer(named); !types.Implements(typ, intf) {
+					continue
+				}
+			}
+			var sel = types.NewMethodSet(typ).Lookup(fn.Pkg(), fn.Name())
+			if sel == nil {
+				continue
+			}
+			if m, ok := self.declOf(sel.Obj()).(*Method); ok && !pkg.isCopy(m.GetFile()) {
+				out = append(out, m)
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,201 @@
+package bast
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCallGraph tests building and querying of the call graph
+func TestCallGraph(t *testing.T) {
+	// Interface call targets depend on the loaded packages, load the calls
+	// package alone instead of using the shared test project.
+	cfg := DefaultConfig()
+	cfg.Dir = "_testproject"
+	bast, err := Load(cfg, "./pkg/calls")
+	if err != nil {
+		t.Fatalf("Failed to load test project: %v", err)
+	}
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/calls")
+	if pkg == nil {
+		t.Fatal("Expected to find calls package")
+	}
+	circleFile, _ := pkg.Files.Get(filepath.Join(pkg.pkg.Dir, "circle.go"))
+	if circleFile == nil {
+		t.Fatal("Expected to find circle.go")
+	}
+
+	var (
+		run        = pkg.Func("Run")
+		totalArea  = pkg.Func("TotalArea")
+		apply      = pkg.Func("Apply")
+		helper     = pkg.Func("helper")
+		newSquare  = pkg.Func("NewSquare")
+		unused     = pkg.Func("Unused")
+		squareArea = pkg.Method("Area")
+		circleArea = circleFile.Method("Area")
+		shapeArea  = pkg.Interface("Shape").Methods.Values()[0]
+	)
+	if squareArea.Receiver == nil || squareArea.Receiver.Type != "Square" {
+		t.Fatal("Expected to find Square.Area")
+	}
+
+	graph := bast.CallGraph()
+
+	t.Run("Nodes", func(t *testing.T) {
+		if len(graph.Nodes) != 8 {
+			t.Errorf("Expected 8 nodes, got %d", len(graph.Nodes))
+		}
+	})
+
+	t.Run("Callees", func(t *testing.T) {
+		callees := graph.Callees(run)
+		if len(callees) != 3 {
+			t.Fatalf("Expected 3 calls from Run, got %d", len(callees))
+		}
+		for i, expected := range []Declaration{totalArea, apply, helper} {
+			if callees[i].Callee != expected || callees[i].Kind != CallStatic {
+				t.Errorf("Unexpected call %d from Run: %s %s", i, callees[i].Kind, callees[i].Expr)
+			}
+		}
+
+		callees = graph.Callees(totalArea)
+		if len(callees) != 1 || callees[0].Kind != CallInterface {
+			t.Fatalf("Expected a single interface call from TotalArea, got %d", len(callees))
+		}
+		if callees[0].Callee != shapeArea || callees[0].Expr != "s.Area" {
+			t.Errorf("Expected call of Shape.Area, got %s", callees[0].Expr)
+		}
+		if targets := callees[0].Targets; len(targets) != 2 || targets[0] != circleArea || targets[1] != squareArea {
+			t.Errorf("Expected Circle.Area and Square.Area targets, got %d", len(targets))
+		}
+
+		callees = graph.Callees(apply)
+		if len(callees) != 1 || callees[0].Kind != CallUnknown || callees[0].Callee != nil || callees[0].Expr != "f" {
+			t.Error("Expected a single unknown call from Apply")
+		}
+
+		callees = graph.Callees(helper)
+		if len(callees) != 2 || callees[0].Callee != squareArea || callees[1].Callee != newSquare {
+			t.Error("Expected calls of Square.Area and NewSquare from helper")
+		}
+	})
+
+	t.Run("Callers", func(t *testing.T) {
+		callers := graph.Callers(helper)
+		if len(callers) != 2 || callers[0].Caller != run || callers[1].Caller != unused {
+			t.Error("Expected helper to be called from Run and Unused")
+		}
+		callers = graph.Callers(circleArea)
+		if len(callers) != 1 || callers[0].Caller != totalArea {
+			t.Error("Expected Circle.Area to be called from TotalArea")
+		}
+		callers = graph.Callers(squareArea)
+		if len(callers) != 2 || callers[0].Caller != totalArea || callers[1].Caller != helper {
+			t.Error("Expected Square.Area to be called from TotalArea and helper")
+		}
+		if callers = graph.Callers(unused); len(callers) != 0 {
+			t.Error("Expected no callers of Unused")
+		}
+	})
+
+	t.Run("Reachable", func(t *testing.T) {
+		reachable := graph.Reachable(run)
+		expected := []Declaration{run, totalArea, apply, helper, shapeArea, circleArea, squareArea, newSquare}
+		if len(reachable) != len(expected) {
+			t.Fatalf("Expected %d reachable, got %d", len(expected), len(reachable))
+		}
+		for i, decl := range expected {
+			if reachable[i] != decl {
+				t.Errorf("Unexpected reachable %d: %s", i, qualifiedName(reachable[i]))
+			}
+		}
+		for _, decl := range reachable {
+			if decl == Declaration(unused) {
+				t.Error("Expected Unused not to be reachable")
+			}
+		}
+	})
+
+	t.Run("DOT", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := graph.WriteDOT(&buf); err != nil {
+			t.Fatal(err)
+		}
+		dot := buf.String()
+		for _, expected := range []string{
+			"digraph callgraph {",
+			`"github.com/vedranvuk/bast/_testproject/pkg/calls.Run" -> "github.com/vedranvuk/bast/_testproject/pkg/calls.helper";`,
+			`"github.com/vedranvuk/bast/_testproject/pkg/calls.TotalArea" -> "github.com/vedranvuk/bast/_testproject/pkg/calls.(*Circle).Area" [style=dashed];`,
+		} {
+			if !strings.Contains(dot, expected) {
+				t.Errorf("Expected DOT output to contain %q:\n%s", expected, dot)
+			}
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(graph)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			Nodes []string
+			Edges []struct {
+				Caller  string
+				Callee  string
+				Targets []string
+				Kind    string
+			}
+		}
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if len(out.Nodes) != len(graph.Nodes) || len(out.Edges) != len(graph.Edges) {
+			t.Fatalf("Unexpected JSON graph size: %s", data)
+		}
+		var kinds = make(map[string]int)
+		for _, edge := range out.Edges {
+			kinds[edge.Kind]++
+		}
+		if kinds["static"] != 6 || kinds["interface"] != 1 || kinds["unknown"] != 1 {
+			t.Errorf("Unexpected edge kinds: %v", kinds)
+		}
+	})
+}
+
+// TestCallGraphEmbeddedInterface tests targets of calls of an embedded
+// interface method through different embedding interfaces
+func TestCallGraphEmbeddedInterface(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	const path = "github.com/vedranvuk/bast/_testproject/pkg/calls/embedded"
+	names := bast.PkgFunc(path, "Names")
+	if names == nil {
+		t.Fatal("Expected to find Names")
+	}
+	method := func(typeName string) *Method {
+		for _, m := range bast.MethodSet(path, typeName) {
+			if m.Name == "Name" {
+				return m
+			}
+		}
+		t.Fatalf("Expected to find %s.Name", typeName)
+		return nil
+	}
+	dog, car := method("Dog"), method("Car")
+
+	callees := bast.CallGraph().Callees(names)
+	if len(callees) != 2 {
+		t.Fatalf("Expected 2 calls from Names, got %d", len(callees))
+	}
+	for i, expected := range []*Method{dog, car} {
+		edge := callees[i]
+		if edge.Kind != CallInterface || len(edge.Targets) != 1 || edge.Targets[0] != expected {
+			t.Errorf("Expected %s to target %s.Name only, got %v", edge.Expr, expected.Receiver.Type, edge.Targets)
+		}
+	}
+}
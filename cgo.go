@@ -0,0 +1,61 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import "strings"
+
+// CgoFlag is a #cgo directive from a cgo preamble, e.g.
+// "#cgo linux LDFLAGS: -lm".
+type CgoFlag struct {
+	// Constraints are the optional build constraints of the directive,
+	// e.g. "linux" or "!windows,amd64".
+	Constraints []string
+	// Name is the name of the flag variable, e.g. "CFLAGS", "LDFLAGS" or
+	// "pkg-config".
+	Name string
+	// Args are the flag arguments.
+	Args []string
+}
+
+// IsCgo returns true if the file imports the "C" pseudo package.
+func (self *File) IsCgo() bool {
+	_, ok := self.Imports.Get("C")
+	return ok
+}
+
+// CgoRefs returns the names of C symbols the declaration references through
+// the cgo "C" pseudo package, e.g. "int" for C.int, in order of appearance.
+//
+// It returns nil if the declaration has no file.
+func (self *Model) CgoRefs() []string {
+	if self.file == nil {
+		return nil
+	}
+	return self.file.cgoRefs[self]
+}
+
+// parseCgoFlags parses #cgo directives from cgo preamble text.
+func parseCgoFlags(preamble string) (out []*CgoFlag) {
+	for _, line := range strings.Split(preamble, "\n") {
+		var directive, ok = strings.CutPrefix(strings.TrimSpace(line), "#cgo")
+		if !ok || directive == "" || (directive[0] != ' ' && directive[0] != '\t') {
+			continue
+		}
+		var decl, args, found = strings.Cut(directive, ":")
+		if !found {
+			continue
+		}
+		var fields = strings.Fields(decl)
+		if len(fields) == 0 {
+			continue
+		}
+		out = append(out, &CgoFlag{
+			Constraints: fields[:len(fields)-1],
+			Name:        fields[len(fields)-1],
+			Args:        strings.Fields(args),
+		})
+	}
+	return
+}
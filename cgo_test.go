@@ -0,0 +1,103 @@
+package bast
+
+import (
+	"go/build"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestCgo tests parsing of cgo files, preambles and C references
+func TestCgo(t *testing.T) {
+	if !build.Default.CgoEnabled {
+		t.Skip("cgo is not enabled")
+	}
+	cc := strings.Fields(os.Getenv("CC"))
+	if len(cc) == 0 {
+		cc = []string{"gcc"}
+	}
+	if _, err := exec.LookPath(cc[0]); err != nil {
+		t.Skipf("C compiler %s not found", cc[0])
+	}
+
+	// The cgo fixture is in testdata so that loading all test project
+	// packages does not require a C compiler.
+	cfg := DefaultConfig()
+	cfg.Dir = "_testproject"
+	bast, err := Load(cfg, "./testdata/cgotest")
+	if err != nil {
+		t.Fatalf("Failed to load test project: %v", err)
+	}
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/testdata/cgotest")
+	if pkg == nil {
+		t.Fatal("Expected to find cgotest package")
+	}
+
+	t.Run("SourceFiles", func(t *testing.T) {
+		var names []string
+		for _, file := range pkg.Files.Values() {
+			names = append(names, filepath.Base(file.Name))
+			if !strings.HasPrefix(file.Name, pkg.pkg.Dir) {
+				t.Errorf("Expected file in package directory, got %s", file.Name)
+			}
+		}
+		if !reflect.DeepEqual(names, []string{"doc.go", "cgotest.go"}) {
+			t.Errorf("Expected original source files, got %v", names)
+		}
+	})
+
+	t.Run("Preamble", func(t *testing.T) {
+		file, _ := pkg.Files.Get(filepath.Join(pkg.pkg.Dir, "cgotest.go"))
+		if file == nil {
+			t.Fatal("Expected to find cgotest.go")
+		}
+		if !file.IsCgo() {
+			t.Error("Expected cgotest.go to be a cgo file")
+		}
+		if !strings.Contains(file.CgoPreamble, "static int add(int a, int b)") {
+			t.Errorf("Unexpected cgo preamble: %q", file.CgoPreamble)
+		}
+		expected := []*CgoFlag{
+			{Constraints: []string{}, Name: "CFLAGS", Args: []string{"-DCGOTEST=1", "-O2"}},
+			{Constraints: []string{"linux"}, Name: "LDFLAGS", Args: []string{"-lm"}},
+		}
+		if !reflect.DeepEqual(file.CgoFlags, expected) {
+			t.Errorf("Unexpected cgo flags: %+v", file.CgoFlags)
+		}
+	})
+
+	t.Run("CgoRefs", func(t *testing.T) {
+		tests := []struct {
+			decl     Declaration
+			expected []string
+		}{
+			{pkg.Struct("Point"), []string{"point"}},
+			{pkg.Func("Add"), []string{"add", "int"}},
+			{pkg.Func("Sqrt"), []string{"sqrt", "double"}},
+			{pkg.Func("Free"), []string{"free"}},
+			{pkg.Func("NoCgo"), nil},
+		}
+		for _, test := range tests {
+			var refs []string
+			switch d := test.decl.(type) {
+			case *Struct:
+				refs = d.CgoRefs()
+			case *Func:
+				refs = d.CgoRefs()
+			}
+			if !reflect.DeepEqual(refs, test.expected) {
+				t.Errorf("Expected C refs %v, got %v", test.expected, refs)
+			}
+		}
+		if file := pkg.Files.Values()[0]; file.IsCgo() {
+			t.Errorf("Expected %s not to be a cgo file", file.Name)
+		}
+		if refs := NewFunc(nil, "NoFile").CgoRefs(); refs != nil {
+			t.Errorf("Expected no C refs for declaration without file, got %v", refs)
+		}
+	})
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Constructors returns the funcs constructing the struct, in order of
+// declaration. See [Func.Constructs].
+func (self *Struct) Constructors() []*Func { return constructorsOf(self.GetPackage(), self.Name) }
+
+// Constructors returns the funcs constructing the type, in order of
+// declaration. See [Func.Constructs].
+func (self *Type) Constructors() []*Func { return constructorsOf(self.GetPackage(), self.Name) }
+
+// Constructors returns the funcs constructing a value implementing the
+// interface, in order of declaration. See [Func.Constructs].
+func (self *Interface) Constructors() []*Func { return constructorsOf(self.GetPackage(), self.Name) }
+
+// Constructs returns the name of the type the func constructs or an empty
+// string if the func is not a constructor.
+//
+// As with go/doc, a func constructs a type T declared in the same package
+// if it returns T or *T, possibly along with values of other packages'
+// types such as error. If the func returns more than one type of the
+// package, the type named by the func name is used, following the NewT and
+// NewTWith... naming convention, e.g. "NewUserWithGroup" constructs User.
+//
+// Func type declarations are never constructors.
+func (self *Func) Constructs() string {
+	if self.isType || self.file == nil {
+		return ""
+	}
+	return constructs(self, self.GetPackage().index().names)
+}
+
+// constructs returns the name of the type fn constructs, looking up
+// package level declarations in names, or an empty string if fn is not a
+// constructor.
+func constructs(fn *Func, names map[string]Declaration) string {
+
+	var candidates []string
+	for _, result := range fn.Results.Values() {
+		var name = baseTypeName(result.Type)
+		if name != "" && isTypeDecl(names[name]) && !slices.Contains(candidates, name) {
+			candidates = append(candidates, name)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return ""
+	case 1:
+		return candidates[0]
+	}
+
+	var out string
+	for _, name := range candidates {
+		if isConstructorName(fn.Name, name) && len(name) > len(out) {
+			out = name
+		}
+	}
+	return out
+}
+
+// constructorsOf returns funcs of pkg constructing type typeName.
+func constructorsOf(pkg *Package, typeName string) []*Func {
+	return slices.Clone(pkg.index().constructors[typeName])
+}
+
+// baseTypeName returns the bare name of a local type from type expression
+// typ, dereferencing a pointer and removing type arguments, or an empty
+// string if typ is not a possibly instantiated local type name.
+func baseTypeName(typ string) string {
+	typ = strings.TrimPrefix(typ, "*")
+	if i := strings.IndexByte(typ, '['); i > 0 {
+		typ = typ[:i]
+	}
+	if typ == "" || strings.ContainsAny(typ, ".*[]() ") {
+		return ""
+	}
+	return typ
+}
+
+// isTypeDecl returns true if decl is a struct, type or interface.
+func isTypeDecl(decl Declaration) bool {
+	switch decl.(type) {
+	case *Struct, *Type, *Interface:
+		return true
+	}
+	return false
+}
+
+// isConstructorName returns true if func name is "New" or "new" followed
+// by typeName with its first letter in upper case, optionally followed by
+// a word starting with an upper case letter, e.g. "NewUser" or
+// "NewUserWithName" for type "User" and "newUser" for type "user".
+func isConstructorName(name, typeName string) bool {
+	var r, size = utf8.DecodeRuneInString(typeName)
+	var title = string(unicode.ToUpper(r)) + typeName[size:]
+	var rest, ok = strings.CutPrefix(name, "New"+title)
+	if !ok {
+		if rest, ok = strings.CutPrefix(name, "new"+title); !ok {
+			return false
+		}
+	}
+	if rest == "" {
+		return true
+	}
+	r, _ = utf8.DecodeRuneInString(rest)
+	return unicode.IsUpper(r)
+}
@@ -0,0 +1,111 @@
+package bast
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestConstructors tests association of constructors with types
+func TestConstructors(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/ctors")
+	if pkg == nil {
+		t.Fatal("Expected to find ctors package")
+	}
+
+	names := func(funcs []*Func) (out []string) {
+		for _, f := range funcs {
+			out = append(out, f.Name)
+		}
+		return
+	}
+
+	t.Run("Constructs", func(t *testing.T) {
+		tests := map[string]string{
+			"NewUser":          "User",
+			"NewUserWithGroup": "User",
+			"LoadUser":         "User",
+			"NewGroup":         "Group",
+			"Pair":             "",
+			"NewStore":         "Store",
+			"ParseID":          "ID",
+			"newUser":          "user",
+			"NewReader":        "",
+			"Users":            "",
+			"NewHandler":       "",
+			"Handler":          "",
+			"MakeList":         "List",
+		}
+		for fn, expected := range tests {
+			f := pkg.Func(fn)
+			if f == nil {
+				t.Fatalf("Expected to find func %s", fn)
+			}
+			if constructs := f.Constructs(); constructs != expected {
+				t.Errorf("%s: expected to construct %q, got %q", fn, expected, constructs)
+			}
+		}
+	})
+
+	t.Run("Constructors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			funcs    []*Func
+			expected string
+		}{
+			{"User", pkg.Struct("User").Constructors(), "NewUser NewUserWithGroup LoadUser"},
+			{"Group", pkg.Struct("Group").Constructors(), "NewGroup"},
+			{"List", pkg.Struct("List").Constructors(), "MakeList"},
+			{"user", pkg.Struct("user").Constructors(), "newUser"},
+			{"ID", pkg.Type("ID").Constructors(), "ParseID"},
+			{"Store", pkg.Interface("Store").Constructors(), "NewStore"},
+		}
+		for _, test := range tests {
+			if got := strings.Join(names(test.funcs), " "); got != test.expected {
+				t.Errorf("%s: expected constructors %q, got %q", test.name, test.expected, got)
+			}
+		}
+	})
+
+	t.Run("Printer", func(t *testing.T) {
+		var buf bytes.Buffer
+		DefaultPrinter().Print(&buf, bast)
+		output := buf.String()
+		for _, expected := range []string{"Constructor  \"NewUserWithGroup\"", "Constructor  \"ParseID\""} {
+			if !strings.Contains(output, expected) {
+				t.Errorf("Expected output to contain %q:\n%s", expected, output)
+			}
+		}
+
+		buf.Reset()
+		printer := DefaultPrinter()
+		printer.PrintConstructors = false
+		printer.Print(&buf, bast)
+		if strings.Contains(buf.String(), "Constructor") {
+			t.Error("Expected output without constructors")
+		}
+	})
+}
+
+// TestIsConstructorName tests constructor naming convention matching
+func TestIsConstructorName(t *testing.T) {
+	tests := []struct {
+		name, typeName string
+		expected       bool
+	}{
+		{"NewUser", "User", true},
+		{"NewUserWithName", "User", true},
+		{"NewUsers", "User", false},
+		{"NewUserStore", "UserStore", true},
+		{"newUser", "user", true},
+		{"NewUser", "user", true},
+		{"MakeUser", "User", false},
+	}
+	for _, test := range tests {
+		if ok := isConstructorName(test.name, test.typeName); ok != test.expected {
+			t.Errorf("isConstructorName(%q, %q): expected %v", test.name, test.typeName, test.expected)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"go/types"
+)
+
+// TypeDependencies returns the declarations of types decl depends on, in
+// order of appearance, or nil if none found.
+//
+// Dependencies are collected from field, embedded, param, result, receiver,
+// underlying and type parameter constraint types, including type arguments
+// of instantiated generic types. Types declared outside of loaded packages
+// and decl itself are not included.
+//
+// decl may be any top-level declaration, a method, an interface method or
+// a field of a top-level struct.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Bast) TypeDependencies(decl Declaration) []Declaration {
+	return self.typeDependencies(decl, false)
+}
+
+// TypeDependents returns the declarations whose types depend on decl, in
+// load order, or nil if none found.
+//
+// It is the inverse of [Bast.TypeDependencies] over top-level declarations,
+// methods, interface methods and struct fields of all loaded packages.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Bast) TypeDependents(decl Declaration) []Declaration {
+	self.dependentsOnce.Do(func() {
+		self.dependents = make(map[Declaration][]Declaration)
+		for _, pkg := range self.packages.Values() {
+			for _, file := range pkg.ownFiles() {
+				for _, d := range file.Declarations.Values() {
+					self.indexDependents(d)
+					switch v := d.(type) {
+					case *Struct:
+						for _, field := range v.Fields.Values() {
+							self.indexDependents(field)
+						}
+					case *Interface:
+						for _, method := range v.Methods.Values() {
+							self.indexDependents(method)
+						}
+					}
+				}
+			}
+		}
+	})
+	return self.dependents[decl]
+}
+
+// DependencyOrder returns decls along with all of their transitive type
+// dependencies, ordered so that each declaration follows the declarations
+// it depends on.
+//
+// Mutually dependent declarations, e.g. recursive types, are ordered by
+// first appearance.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Bast) DependencyOrder(decls ...Declaration) (out []Declaration) {
+	var (
+		seen  = make(map[Declaration]bool)
+		visit func(decl Declaration)
+	)
+	visit = func(decl Declaration) {
+		if seen[decl] {
+			return
+		}
+		seen[decl] = true
+		for _, dep := range self.TypeDependencies(decl) {
+			visit(dep)
+		}
+		out = append(out, decl)
+	}
+	for _, decl := range decls {
+		visit(decl)
+	}
+	return
+}
+
+// typeDependencies returns type dependencies of decl, including decl itself
+// if it depends on itself and withSelf is true.
+func (self *Bast) typeDependencies(decl Declaration, withSelf bool) (out []Declaration) {
+
+	var (
+		seen = map[Declaration]bool{decl: !withSelf}
+		add  = func(obj *types.TypeName) {
+			if d := self.declOf(obj); d != nil && !seen[d] {
+				seen[d] = true
+				out = append(out, d)
+			}
+		}
+	)
+
+	switch o := objectOf(decl).(type) {
+	case *types.TypeName:
+		switch t := o.Type().(type) {
+		case *types.Named:
+			walkTypeParams(t.TypeParams(), add)
+			walkType(t.Underlying(), add)
+		case *types.Alias:
+			walkTypeParams(t.TypeParams(), add)
+			walkType(t.Rhs(), add)
+		}
+	case *types.Func:
+		var sig = o.Signature()
+		if recv := sig.Recv(); recv != nil {
+			walkType(recv.Type(), add)
+		}
+		walkTypeParams(sig.RecvTypeParams(), add)
+		walkTypeParams(sig.TypeParams(), add)
+		walkType(sig, add)
+	case *types.Var:
+		walkType(o.Type(), add)
+	case *types.Const:
+		walkType(o.Type(), add)
+	}
+
+	return
+}
+
+// indexDependents adds decl as a dependent of its type dependencies.
+func (self *Bast) indexDependents(decl Declaration) {
+	for _, dep := range self.TypeDependencies(decl) {
+		self.dependents[dep] = append(self.dependents[dep], decl)
+	}
+}
+
+// walkType calls f for type names of named types and aliases referenced
+// by t, without descending into their declarations.
+func walkType(t types.Type, f func(obj *types.TypeName)) {
+	switch v := t.(type) {
+	case *types.Named:
+		f(v.Obj())
+		walkTypeList(v.TypeArgs(), f)
+	case *types.Alias:
+		f(v.Obj())
+		walkTypeList(v.TypeArgs(), f)
+	case *types.Pointer:
+		walkType(v.Elem(), f)
+	case *types.Slice:
+		walkType(v.Elem(), f)
+	case *types.Array:
+		walkType(v.Elem(), f)
+	case *types.Map:
+		walkType(v.Key(), f)
+		walkType(v.Elem(), f)
+	case *types.Chan:
+		walkType(v.Elem(), f)
+	case *types.Signature:
+		walkType(v.Params(), f)
+		walkType(v.Results(), f)
+	case *types.Tuple:
+		for i := 0; i < v.Len(); i++ {
+			walkType(v.At(i).Type(), f)
+		}
+	case *types.Struct:
+		for i := 0; i < v.NumFields(); i++ {
+			walkType(v.Field(i).Type(), f)
+		}
+	case *types.Interface:
+		for i := 0; i < v.NumExplicitMethods(); i++ {
+			walkType(v.ExplicitMethod(i).Type(), f)
+		}
+		for i := 0; i < v.NumEmbeddeds(); i++ {
+			walkType(v.EmbeddedType(i), f)
+		}
+	case *types.Union:
+		for i := 0; i < v.Len(); i++ {
+			walkType(v.Term(i).Type(), f)
+		}
+	}
+}
+
+// walkTypeList calls walkType for each type in list.
+func walkTypeList(list *types.TypeList, f func(obj *types.TypeName)) {
+	for i := 0; i < list.Len(); i++ {
+		walkType(list.At(i), f)
+	}
+}
+
+// walkTypeParams calls walkType for constraints of each type parameter in
+// list.
+func walkTypeParams(list *types.TypeParamList, f func(obj *types.TypeName)) {
+	for i := 0; i < list.Len(); i++ {
+		walkType(list.At(i).Constraint(), f)
+	}
+}
@@ -0,0 +1,80 @@
+package bast
+
+import (
+	"testing"
+)
+
+// TestTypeDependencies tests type dependency queries and ordering
+func TestTypeDependencies(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/deps")
+	if pkg == nil {
+		t.Fatal("Expected to find deps package")
+	}
+
+	var (
+		id          = bast.PkgType("github.com/vedranvuk/bast/_testproject/pkg/types", "ID")
+		status      = pkg.Type("Status")
+		country     = pkg.Struct("Country")
+		address     = pkg.Struct("Address")
+		base        = pkg.Struct("Base")
+		tag         = pkg.Type("Tag")
+		customer    = pkg.Struct("Customer")
+		keyed       = pkg.Interface("Keyed")
+		set         = pkg.Struct("Set")
+		repository  = pkg.Interface("Repository")
+		handler     = pkg.Func("Handler")
+		defCountry  = pkg.Var("DefaultCountry")
+		newCustomer = pkg.Func("NewCustomer")
+		valid       = pkg.Method("Valid")
+	)
+	if id == nil {
+		t.Fatal("Expected to find types.ID")
+	}
+
+	equal := func(t *testing.T, name string, got []Declaration, expected ...Declaration) {
+		t.Helper()
+		if len(got) != len(expected) {
+			var names []string
+			for _, d := range got {
+				names = append(names, qualifiedName(d))
+			}
+			t.Errorf("%s: expected %d declarations, got %v", name, len(expected), names)
+			return
+		}
+		for i := range expected {
+			if got[i] != expected[i] {
+				t.Errorf("%s: unexpected declaration %d: %s", name, i, qualifiedName(got[i]))
+			}
+		}
+	}
+
+	t.Run("Dependencies", func(t *testing.T) {
+		equal(t, "Customer", bast.TypeDependencies(customer), base, status, address, tag)
+		equal(t, "Address", bast.TypeDependencies(address), country)
+		equal(t, "Base", bast.TypeDependencies(base), id)
+		equal(t, "Status", bast.TypeDependencies(status))
+		equal(t, "Set", bast.TypeDependencies(set), keyed)
+		equal(t, "Repository", bast.TypeDependencies(repository), id, customer, keyed)
+		equal(t, "Handler", bast.TypeDependencies(handler), customer)
+		equal(t, "DefaultCountry", bast.TypeDependencies(defCountry), country)
+		equal(t, "NewCustomer", bast.TypeDependencies(newCustomer), address, customer)
+		equal(t, "Valid", bast.TypeDependencies(valid), customer)
+		field, _ := customer.Fields.Get("Addresses")
+		equal(t, "Customer.Addresses", bast.TypeDependencies(field), address)
+	})
+
+	t.Run("Dependents", func(t *testing.T) {
+		field, _ := customer.Fields.Get("Addresses")
+		equal(t, "Address", bast.TypeDependents(address), customer, field, newCustomer)
+		tags, _ := customer.Fields.Get("Tags")
+		equal(t, "Tag", bast.TypeDependents(tag), customer, tags, keyed, keyed.Methods.Values()[0])
+		equal(t, "Set", bast.TypeDependents(set))
+	})
+
+	t.Run("DependencyOrder", func(t *testing.T) {
+		equal(t, "Customer", bast.DependencyOrder(customer), id, base, status, country, address, tag, customer)
+		equal(t, "Set, Address", bast.DependencyOrder(set, address), tag, keyed, set, country, address)
+	})
+}
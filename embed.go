@@ -0,0 +1,153 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// EmbedKind specifies the type of a variable with a //go:embed directive.
+type EmbedKind int
+
+const (
+	// EmbedUnknown is an embed into a variable of unsupported type.
+	EmbedUnknown EmbedKind = iota
+	// EmbedString is an embed into a string variable.
+	EmbedString
+	// EmbedBytes is an embed into a []byte variable.
+	EmbedBytes
+	// EmbedFS is an embed into an embed.FS variable.
+	EmbedFS
+)
+
+// String implements fmt.Stringer.
+func (self EmbedKind) String() string {
+	switch self {
+	case EmbedString:
+		return "string"
+	case EmbedBytes:
+		return "[]byte"
+	case EmbedFS:
+		return "embed.FS"
+	}
+	return "unknown"
+}
+
+// Embed describes the //go:embed directives of a variable.
+type Embed struct {
+	// Patterns are the embed patterns from all directives of the variable,
+	// in order of appearance.
+	Patterns []string
+	// Files are the absolute paths of the files matched by Patterns.
+	//
+	// Files are resolved from the package embed files and are only
+	// available for packages loaded using [Load].
+	Files []string
+	// Kind is the type of embedding variable.
+	Kind EmbedKind
+}
+
+// parseEmbedDirectives returns embed patterns from //go:embed directives
+// found in comments or nil if there are none.
+func parseEmbedDirectives(comments []string) (out []string) {
+	for _, comment := range comments {
+		var args, ok = strings.CutPrefix(comment, "//go:embed")
+		if !ok || (args != "" && args[0] != ' ' && args[0] != '\t') {
+			continue
+		}
+		out = append(out, parseEmbedPatterns(args)...)
+	}
+	return
+}
+
+// parseEmbedPatterns parses space separated embed patterns that may be
+// double or back quoted.
+func parseEmbedPatterns(args string) (out []string) {
+	for args = strings.TrimSpace(args); args != ""; args = strings.TrimSpace(args) {
+		if args[0] != '"' && args[0] != '`' {
+			var pattern, rest, _ = strings.Cut(args, " ")
+			out = append(out, strings.TrimSpace(pattern))
+			args = rest
+			continue
+		}
+		var quoted, err = strconv.QuotedPrefix(args)
+		if err != nil {
+			return
+		}
+		var pattern, _ = strconv.Unquote(quoted)
+		out = append(out, pattern)
+		args = args[len(quoted):]
+	}
+	return
+}
+
+// embedKind returns the embed kind for a variable of type typ declared in
+// file.
+func embedKind(file *File, typ string) EmbedKind {
+	switch typ {
+	case "string":
+		return EmbedString
+	case "[]byte", "[]uint8":
+		return EmbedBytes
+	}
+	if pkg, name, ok := strings.Cut(typ, "."); ok && name == "FS" {
+		for _, imp := range file.Imports.Values() {
+			if imp.Path == "embed" && (imp.Name == pkg || pkg == "embed") {
+				return EmbedFS
+			}
+		}
+	}
+	return EmbedUnknown
+}
+
+// matchEmbedFiles returns files from embedFiles, absolute paths of all files
+// embedded by the package in directory dir, which are matched by patterns.
+//
+// Matching follows go:embed rules; files matched through a directory
+// exclude files whose names begin with '.' or '_' unless prefixed by "all:".
+func matchEmbedFiles(dir string, patterns, embedFiles []string) (out []string) {
+	for _, file := range embedFiles {
+		var rel, err = filepath.Rel(dir, file)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, pattern := range patterns {
+			if matchEmbedFile(pattern, rel) {
+				out = append(out, file)
+				break
+			}
+		}
+	}
+	return
+}
+
+// matchEmbedFile returns true if file path rel, relative to package
+// directory, is matched by embed pattern.
+func matchEmbedFile(pattern, rel string) bool {
+	var pattern2, all = strings.CutPrefix(pattern, "all:")
+	if ok, _ := path.Match(pattern2, rel); ok {
+		return true
+	}
+	var elems = strings.Split(rel, "/")
+	for i := 1; i < len(elems); i++ {
+		if ok, _ := path.Match(pattern2, strings.Join(elems[:i], "/")); !ok {
+			continue
+		}
+		if all {
+			return true
+		}
+		for _, elem := range elems[i:] {
+			if strings.HasPrefix(elem, ".") || strings.HasPrefix(elem, "_") {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
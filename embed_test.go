@@ -0,0 +1,83 @@
+package bast
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestEmbed tests parsing of //go:embed directives on variables
+func TestEmbed(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/assets")
+	if pkg == nil {
+		t.Fatal("Expected to find assets package")
+	}
+
+	relFiles := func(files []string) (out []string) {
+		for _, file := range files {
+			rel, err := filepath.Rel(pkg.pkg.Dir, file)
+			if err != nil {
+				t.Fatalf("Unexpected embed file path: %s", file)
+			}
+			out = append(out, filepath.ToSlash(rel))
+		}
+		return
+	}
+
+	tests := []struct {
+		name     string
+		kind     EmbedKind
+		patterns []string
+		files    []string
+	}{
+		{"Version", EmbedString, []string{"version.txt"}, []string{"version.txt"}},
+		{"Logo", EmbedBytes, []string{"static/logo.svg"}, []string{"static/logo.svg"}},
+		{"Static", EmbedFS, []string{"static", "version.txt"}, []string{"static/index.html", "static/logo.svg", "version.txt"}},
+		{"All", EmbedFS, []string{"all:static"}, []string{"static/.hidden", "static/_partial.html", "static/index.html", "static/logo.svg"}},
+	}
+	for _, test := range tests {
+		v := pkg.Var(test.name)
+		if v == nil {
+			t.Errorf("Expected to find var %s", test.name)
+			continue
+		}
+		if v.Embed == nil {
+			t.Errorf("Expected var %s to have embed", test.name)
+			continue
+		}
+		if v.Embed.Kind != test.kind {
+			t.Errorf("Expected var %s embed kind %s, got %s", test.name, test.kind, v.Embed.Kind)
+		}
+		if !reflect.DeepEqual(v.Embed.Patterns, test.patterns) {
+			t.Errorf("Expected var %s embed patterns %v, got %v", test.name, test.patterns, v.Embed.Patterns)
+		}
+		if files := relFiles(v.Embed.Files); !reflect.DeepEqual(files, test.files) {
+			t.Errorf("Expected var %s embed files %v, got %v", test.name, test.files, files)
+		}
+	}
+
+	if v := pkg.Var("NotEmbedded"); v == nil || v.Embed != nil {
+		t.Error("Expected NotEmbedded var without embed")
+	}
+}
+
+// TestParseEmbedPatterns tests parsing of go:embed directive arguments
+func TestParseEmbedPatterns(t *testing.T) {
+	tests := []struct {
+		comments []string
+		expected []string
+	}{
+		{[]string{"//go:embed a.txt b/*.html"}, []string{"a.txt", "b/*.html"}},
+		{[]string{`//go:embed "with space.txt" ` + "`raw.txt`"}, []string{"with space.txt", "raw.txt"}},
+		{[]string{"//go:embed a", "// comment", "//go:embed b"}, []string{"a", "b"}},
+		{[]string{"//go:embedded a"}, nil},
+		{[]string{"// go:embed a"}, nil},
+	}
+	for _, test := range tests {
+		if result := parseEmbedDirectives(test.comments); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("parseEmbedDirectives(%q) = %q, expected %q", test.comments, result, test.expected)
+		}
+	}
+}
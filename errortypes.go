@@ -0,0 +1,198 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strconv"
+)
+
+// errorInterface is the predeclared error interface.
+var errorInterface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// ErrorType is a type implementing the error interface.
+type ErrorType struct {
+	// Type is the implementing *Struct or *Type declaration.
+	Type Declaration
+	// Pointer is true if only the pointer to Type implements error.
+	Pointer bool
+	// Error is the Error() string method, nil if it is promoted from a
+	// package that was not loaded.
+	Error *Method
+	// Unwrap is the Unwrap() error or Unwrap() []error method, nil if
+	// not declared.
+	Unwrap *Method
+	// Is is the Is(error) bool method, nil if not declared.
+	Is *Method
+	// As is the As(any) bool method, nil if not declared.
+	As *Method
+}
+
+// SentinelError is a package level var initialized with a new error.
+type SentinelError struct {
+	// Var is the sentinel error var.
+	Var *Var
+	// Func is the func creating the error, "errors.New" or "fmt.Errorf".
+	Func string
+	// Message is the unquoted error message or format string passed to
+	// Func, empty if it is not a string literal.
+	Message string
+}
+
+// ErrorTypes returns the struct and type declarations of all loaded packages
+// that implement the error interface by value or pointer, in load order.
+//
+// If type checking was enabled, a type implements error if its method set,
+// including methods promoted from embedded fields, satisfies the error
+// interface. Otherwise, and for generic types, a type implements error if it
+// declares an Error() string method.
+//
+// If packages were loaded with [Config.Tests], error types declared in test
+// files are included.
+func (self *Bast) ErrorTypes() (out []*ErrorType) {
+
+	for _, pkg := range self.packages.Values() {
+		for _, file := range pkg.ownFiles() {
+			for _, decl := range file.Declarations.Values() {
+				var name string
+				switch d := decl.(type) {
+				case *Struct:
+					name = d.Name
+				case *Type:
+					name = d.Name
+				default:
+					continue
+				}
+				if et := self.errorTypeOf(pkg, decl, name); et != nil {
+					out = append(out, et)
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// SentinelErrors returns package level vars of all loaded packages
+// initialized with a call to errors.New or fmt.Errorf, in load order.
+func (self *Bast) SentinelErrors() (out []*SentinelError) {
+
+	for _, v := range self.AllVars() {
+		var call, ok = parseVarValue(v.Value).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		var sel, _ = call.Fun.(*ast.SelectorExpr)
+		if sel == nil {
+			continue
+		}
+		var x, _ = sel.X.(*ast.Ident)
+		if x == nil {
+			continue
+		}
+		var imp = v.ImportSpecBySelectorExpr(x.Name + "." + sel.Sel.Name)
+		if imp == nil {
+			continue
+		}
+		var fn = imp.Path + "." + sel.Sel.Name
+		if fn != "errors.New" && fn != "fmt.Errorf" {
+			continue
+		}
+		var err = &SentinelError{Var: v, Func: fn}
+		if len(call.Args) > 0 {
+			if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				err.Message, _ = strconv.Unquote(lit.Value)
+			}
+		}
+		out = append(out, err)
+	}
+
+	return
+}
+
+// errorTypeOf returns the error type of decl named name in pkg or nil if
+// decl does not implement error.
+func (self *Bast) errorTypeOf(pkg *Package, decl Declaration, name string) *ErrorType {
+
+	var named = namedOf(objectOf(decl))
+	if named == nil || named.TypeParams().Len() > 0 {
+		if et := errorType(decl, self.MethodSet(pkg.ID, name)); et.Error != nil {
+			return et
+		}
+		return nil
+	}
+
+	var (
+		typ     types.Type = named
+		pointer bool
+	)
+	if !types.Implements(typ, errorInterface) {
+		if typ, pointer = types.NewPointer(named), true; !types.Implements(typ, errorInterface) {
+			return nil
+		}
+	}
+
+	var (
+		set     = types.NewMethodSet(typ)
+		methods []*Method
+	)
+	for i := 0; i < set.Len(); i++ {
+		if m, ok := self.declOf(set.At(i).Obj()).(*Method); ok {
+			methods = append(methods, m)
+		}
+	}
+	var out = errorType(decl, methods)
+	out.Pointer = pointer
+
+	return out
+}
+
+// errorType returns the error type of decl with given methods. Error is nil
+// if methods do not include an Error() string method.
+func errorType(decl Declaration, methods []*Method) *ErrorType {
+
+	var out = &ErrorType{Type: decl}
+	for _, m := range methods {
+		switch {
+		case m.Name == "Error" && hasSignature(&m.Func, nil, "string"):
+			out.Error, out.Pointer = m, m.Receiver.Pointer
+		case m.Name == "Unwrap" && (hasSignature(&m.Func, nil, "error") || hasSignature(&m.Func, nil, "[]error")):
+			out.Unwrap = m
+		case m.Name == "Is" && hasSignature(&m.Func, []string{"error"}, "bool"):
+			out.Is = m
+		case m.Name == "As" && (hasSignature(&m.Func, []string{"any"}, "bool") || hasSignature(&m.Func, []string{"interface{}"}, "bool")):
+			out.As = m
+		}
+	}
+
+	return out
+}
+
+// hasSignature returns true if fn has params of types params and a single
+// result of type result.
+func hasSignature(fn *Func, params []string, result string) bool {
+	if fn.Params.Len() != len(params) || fn.Results.Len() != 1 || fn.Results.Values()[0].Type != result {
+		return false
+	}
+	for i, param := range fn.Params.Values() {
+		if param.Type != params[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// parseVarValue parses var value expression value, returning nil if value
+// is not a valid expression.
+func parseVarValue(value string) ast.Expr {
+	var expr, err = parser.ParseExpr(value)
+	if err != nil {
+		return nil
+	}
+	return expr
+}
@@ -0,0 +1,171 @@
+package bast
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestErrorTypes tests discovery of error types and sentinel errors
+func TestErrorTypes(t *testing.T) {
+	const path = "github.com/vedranvuk/bast/_testproject/pkg/errortest"
+
+	// errorTypes returns error types of the errortest package and its
+	// external test package.
+	var errorTypes = func(bast *Bast) (out []*ErrorType) {
+		for _, et := range bast.ErrorTypes() {
+			if p := et.Type.GetPackage().Path; p == path || p == path+"_test" {
+				out = append(out, et)
+			}
+		}
+		return
+	}
+
+	// sentinelErrors returns sentinel errors of the errortest package.
+	var sentinelErrors = func(bast *Bast) (out []*SentinelError) {
+		for _, s := range bast.SentinelErrors() {
+			if s.Var.GetPackage().Path == path {
+				out = append(out, s)
+			}
+		}
+		return
+	}
+
+	t.Run("ErrorTypes", func(t *testing.T) {
+		tests := map[string]struct {
+			pointer        bool
+			unwrap, is, as bool
+		}{
+			"ValueError": {},
+			"WrapError":  {pointer: true, unwrap: true, is: true, as: true},
+			"CodeError":  {},
+			"EmbedError": {},
+		}
+		types := errorTypes(loadTestProject(t, false))
+		if len(types) != len(tests) {
+			t.Fatalf("Expected %d error types, got %d", len(tests), len(types))
+		}
+		for _, et := range types {
+			var name = strings.TrimPrefix(qualifiedName(et.Type), path+".")
+			test, ok := tests[name]
+			if !ok {
+				t.Errorf("Unexpected error type %s", name)
+				continue
+			}
+			if et.Error == nil || et.Pointer != test.pointer {
+				t.Errorf("%s: expected Error method with pointer %v", name, test.pointer)
+			}
+			if (et.Unwrap != nil) != test.unwrap || (et.Is != nil) != test.is || (et.As != nil) != test.as {
+				t.Errorf("%s: unexpected Unwrap/Is/As methods", name)
+			}
+		}
+	})
+
+	t.Run("Promoted", func(t *testing.T) {
+		for _, et := range errorTypes(loadTestProject(t, false)) {
+			if qualifiedName(et.Type) != path+".EmbedError" {
+				continue
+			}
+			if et.Error == nil || et.Error.Receiver.Type != "ValueError" {
+				t.Errorf("Expected Error method promoted from ValueError, got %v", et.Error)
+			}
+			return
+		}
+		t.Error("EmbedError not found")
+	})
+
+	t.Run("NoTypeChecking", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Dir = "_testproject"
+		cfg.TypeChecking = false
+		bast, err := Load(cfg, "./pkg/errortest")
+		if err != nil {
+			t.Fatalf("Failed to load test project: %v", err)
+		}
+		// Without type information promoted methods are not considered.
+		var names []string
+		for _, et := range errorTypes(bast) {
+			names = append(names, strings.TrimPrefix(qualifiedName(et.Type), path+"."))
+		}
+		if got := strings.Join(names, " "); got != "CodeError ValueError WrapError" {
+			t.Errorf("Expected CodeError ValueError WrapError, got %s", got)
+		}
+	})
+
+	t.Run("Tests", func(t *testing.T) {
+		tests := map[string]struct {
+			pkgPath string
+			pointer bool
+		}{
+			"ValueError": {pkgPath: path},
+			"WrapError":  {pkgPath: path, pointer: true},
+			"CodeError":  {pkgPath: path},
+			"EmbedError": {pkgPath: path},
+			"testError":  {pkgPath: path},
+			"xErr":       {pkgPath: path + "_test", pointer: true},
+		}
+		types := errorTypes(loadTestProject(t, true))
+		if len(types) != len(tests) {
+			t.Fatalf("Expected %d error types, got %d", len(tests), len(types))
+		}
+		for _, et := range types {
+			var (
+				pkg  = et.Type.GetPackage()
+				name = strings.TrimPrefix(qualifiedName(et.Type), pkg.ID+".")
+			)
+			test, ok := tests[name]
+			if !ok {
+				t.Errorf("Unexpected error type %s", name)
+				continue
+			}
+			if pkg.Path != test.pkgPath {
+				t.Errorf("%s: expected package %s, got %s", name, test.pkgPath, pkg.Path)
+			}
+			if et.Error == nil || et.Pointer != test.pointer {
+				t.Errorf("%s: expected Error method with pointer %v", name, test.pointer)
+			}
+		}
+		if n := len(sentinelErrors(loadTestProject(t, true))); n != 3 {
+			t.Errorf("Expected 3 sentinel errors with tests, got %d", n)
+		}
+	})
+
+	t.Run("SentinelErrors", func(t *testing.T) {
+		tests := []struct {
+			name, fn, message string
+		}{
+			{"ErrNotFound", "errors.New", "not found"},
+			{"ErrInvalid", "fmt.Errorf", "invalid input: %w"},
+			{"ErrAliased", "errors.New", "aliased"},
+		}
+		sentinels := sentinelErrors(loadTestProject(t, false))
+		if len(sentinels) != len(tests) {
+			t.Fatalf("Expected %d sentinel errors, got %d", len(tests), len(sentinels))
+		}
+		for i, test := range tests {
+			var s = sentinels[i]
+			if s.Var.Name != test.name || s.Func != test.fn || s.Message != test.message {
+				t.Errorf("Expected %s %s %q, got %s %s %q", test.name, test.fn, test.message, s.Var.Name, s.Func, s.Message)
+			}
+		}
+	})
+
+	t.Run("DuplicateImport", func(t *testing.T) {
+		// Imports are keyed by path so the aliased import of "errors"
+		// replaces the unnamed one and "errors.New" resolves only by the
+		// assumed name of the aliased import.
+		sentinels := sentinelErrors(loadTestProject(t, false))
+		if len(sentinels) == 0 {
+			t.Fatal("No sentinel errors")
+		}
+		var imp = sentinels[0].Var.ImportSpecBySelectorExpr("errors.New")
+		if imp == nil {
+			t.Fatal("errors.New not resolved")
+		}
+		if imp.Name != "stderrors" || imp.AssumedName() != "errors" {
+			t.Errorf("Expected import stderrors with assumed name errors, got %s %s", imp.Name, imp.AssumedName())
+		}
+		if imp != sentinels[0].Var.ImportSpecBySelectorExpr("stderrors.New") {
+			t.Error("Expected errors.New and stderrors.New to resolve to the same import")
+		}
+	})
+}
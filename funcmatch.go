@@ -0,0 +1,398 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"path"
+	"strings"
+	"unicode"
+)
+
+// FuncsMatching returns funcs, methods and interface methods of all loaded
+// packages whose signatures match signature pattern sig, in load order.
+//
+// sig is a func type, e.g. "func(context.Context, *T) (*U, error)".
+// Receivers are not matched and parameter names in sig are ignored.
+//
+// Wildcards are "_", which matches any type, and identifiers of a single
+// upper case letter optionally followed by digits, e.g. "T" or "T1", which
+// match any type but must match identical types at each occurrence.
+//
+// Types are qualified by package name or the last element of package
+// import path. Unqualified type names other than predeclared types match
+// types declared in the package of the func.
+//
+// If Config.TypeChecking is enabled types are matched by type identity,
+// otherwise by their syntax as declared.
+//
+// It returns an error if sig is not a valid func type.
+func (self *Bast) FuncsMatching(sig string) (out []Declaration, err error) {
+
+	var expr ast.Expr
+	if expr, err = parser.ParseExpr(sig); err != nil {
+		return nil, fmt.Errorf("invalid signature pattern: %w", err)
+	}
+	var pattern, ok = expr.(*ast.FuncType)
+	if !ok {
+		return nil, fmt.Errorf("invalid signature pattern: %s is not a func type", sig)
+	}
+
+	var match = func(decl Declaration, fn *Func) {
+		if newSigMatcher(decl).matchFunc(pattern, fn) {
+			out = append(out, decl)
+		}
+	}
+	for _, pkg := range self.packages.Values() {
+		for _, file := range pkg.ownFiles() {
+			for _, decl := range file.Declarations.Values() {
+				switch d := decl.(type) {
+				case *Func:
+					if !d.isType {
+						match(d, d)
+					}
+				case *Method:
+					match(d, &d.Func)
+				case *Interface:
+					for _, m := range d.Methods.Values() {
+						match(m, &m.Func)
+					}
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// sigMatcher matches a signature pattern against a func or method.
+type sigMatcher struct {
+	// pkg is the type checked package of the func, nil if the func is
+	// matched by syntax.
+	pkg *types.Package
+	// obj is the type checked func, nil if the func is matched by syntax.
+	obj *types.Func
+	// bound are the types bound to wildcards when matching by type.
+	bound map[string]types.Type
+	// boundSyntax are the type expressions bound to wildcards when
+	// matching by syntax.
+	boundSyntax map[string]string
+}
+
+// newSigMatcher returns a new sigMatcher for decl.
+func newSigMatcher(decl Declaration) *sigMatcher {
+	var out = &sigMatcher{
+		bound:       make(map[string]types.Type),
+		boundSyntax: make(map[string]string),
+	}
+	if obj, ok := objectOf(decl).(*types.Func); ok {
+		out.obj, out.pkg = obj, obj.Pkg()
+	}
+	return out
+}
+
+// matchFunc returns true if the signature of fn matches pattern.
+func (self *sigMatcher) matchFunc(pattern *ast.FuncType, fn *Func) bool {
+
+	if self.obj != nil {
+		return self.matchSignature(pattern, self.obj.Signature())
+	}
+
+	var (
+		params  = patternTypes(pattern.Params)
+		results = patternTypes(pattern.Results)
+	)
+	if len(params) != fn.Params.Len() || len(results) != fn.Results.Len() {
+		return false
+	}
+	for i, field := range fn.Params.Values() {
+		if !self.matchSyntax(params[i], parseTypeExpr(field.Type)) {
+			return false
+		}
+	}
+	for i, field := range fn.Results.Values() {
+		if !self.matchSyntax(results[i], parseTypeExpr(field.Type)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchSignature returns true if sig matches pattern by type.
+func (self *sigMatcher) matchSignature(pattern *ast.FuncType, sig *types.Signature) bool {
+
+	var (
+		params  = patternTypes(pattern.Params)
+		results = patternTypes(pattern.Results)
+	)
+	if len(params) != sig.Params().Len() || len(results) != sig.Results().Len() {
+		return false
+	}
+	var variadic bool
+	if len(params) > 0 {
+		_, variadic = params[len(params)-1].(*ast.Ellipsis)
+	}
+	if variadic != sig.Variadic() {
+		return false
+	}
+
+	for i, p := range params {
+		var t = sig.Params().At(i).Type()
+		if e, ok := p.(*ast.Ellipsis); ok {
+			var s, _ = t.(*types.Slice)
+			if s == nil {
+				return false
+			}
+			p, t = e.Elt, s.Elem()
+		}
+		if !self.matchType(p, t) {
+			return false
+		}
+	}
+	for i, r := range results {
+		if !self.matchType(r, sig.Results().At(i).Type()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchType returns true if type t matches type pattern expr.
+func (self *sigMatcher) matchType(expr ast.Expr, t types.Type) bool {
+
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return self.matchType(e.X, t)
+	case *ast.Ident:
+		if isWildcard(e.Name) {
+			if e.Name == "_" {
+				return true
+			}
+			if bound, ok := self.bound[e.Name]; ok {
+				return types.Identical(bound, t)
+			}
+			self.bound[e.Name] = t
+			return true
+		}
+		if obj, ok := types.Universe.Lookup(e.Name).(*types.TypeName); ok {
+			return types.Identical(obj.Type(), t)
+		}
+		var named, _ = types.Unalias(t).(*types.Named)
+		return named != nil && named.TypeArgs().Len() == 0 &&
+			named.Obj().Pkg() == self.pkg && named.Obj().Name() == e.Name
+	case *ast.SelectorExpr:
+		var (
+			qual, _  = e.X.(*ast.Ident)
+			named, _ = types.Unalias(t).(*types.Named)
+		)
+		if qual == nil || named == nil || named.TypeArgs().Len() > 0 || named.Obj().Pkg() == nil {
+			return false
+		}
+		var pkg = named.Obj().Pkg()
+		return named.Obj().Name() == e.Sel.Name &&
+			(pkg.Name() == qual.Name || path.Base(pkg.Path()) == qual.Name)
+	case *ast.IndexExpr:
+		return self.matchInstance(e.X, []ast.Expr{e.Index}, t)
+	case *ast.IndexListExpr:
+		return self.matchInstance(e.X, e.Indices, t)
+	case *ast.StarExpr:
+		var p, ok = t.(*types.Pointer)
+		return ok && self.matchType(e.X, p.Elem())
+	case *ast.ArrayType:
+		if e.Len == nil {
+			var s, ok = t.(*types.Slice)
+			return ok && self.matchType(e.Elt, s.Elem())
+		}
+		var a, ok = t.(*types.Array)
+		return ok && types.ExprString(e.Len) == fmt.Sprint(a.Len()) && self.matchType(e.Elt, a.Elem())
+	case *ast.MapType:
+		var m, ok = t.(*types.Map)
+		return ok && self.matchType(e.Key, m.Key()) && self.matchType(e.Value, m.Elem())
+	case *ast.ChanType:
+		var c, ok = t.(*types.Chan)
+		if !ok {
+			return false
+		}
+		var dir = types.SendRecv
+		switch e.Dir {
+		case ast.SEND:
+			dir = types.SendOnly
+		case ast.RECV:
+			dir = types.RecvOnly
+		}
+		return c.Dir() == dir && self.matchType(e.Value, c.Elem())
+	case *ast.FuncType:
+		var sig, ok = t.(*types.Signature)
+		return ok && self.matchSignature(e, sig)
+	case *ast.InterfaceType:
+		var it, ok = t.Underlying().(*types.Interface)
+		return ok && len(e.Methods.List) == 0 && it.Empty()
+	}
+
+	return false
+}
+
+// matchInstance returns true if t is an instance of generic type x with
+// type arguments matching args.
+func (self *sigMatcher) matchInstance(x ast.Expr, args []ast.Expr, t types.Type) bool {
+	var named, _ = types.Unalias(t).(*types.Named)
+	if named == nil || named.TypeArgs().Len() != len(args) || !self.matchType(x, named.Origin()) {
+		return false
+	}
+	for i, arg := range args {
+		if !self.matchType(arg, named.TypeArgs().At(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchSyntax returns true if type expression expr matches type pattern
+// pattern by syntax.
+func (self *sigMatcher) matchSyntax(pattern, expr ast.Expr) bool {
+
+	if p, ok := pattern.(*ast.ParenExpr); ok {
+		return self.matchSyntax(p.X, expr)
+	}
+	if e, ok := expr.(*ast.ParenExpr); ok {
+		return self.matchSyntax(pattern, e.X)
+	}
+	if expr == nil {
+		return false
+	}
+
+	switch p := pattern.(type) {
+	case *ast.Ident:
+		if !isWildcard(p.Name) {
+			var e, ok = expr.(*ast.Ident)
+			return ok && e.Name == p.Name
+		}
+		if p.Name == "_" {
+			return true
+		}
+		var s = types.ExprString(expr)
+		if bound, ok := self.boundSyntax[p.Name]; ok {
+			return bound == s
+		}
+		self.boundSyntax[p.Name] = s
+		return true
+	case *ast.SelectorExpr:
+		var e, ok = expr.(*ast.SelectorExpr)
+		return ok && types.ExprString(p) == types.ExprString(e)
+	case *ast.IndexExpr:
+		var e, ok = expr.(*ast.IndexExpr)
+		return ok && self.matchSyntax(p.X, e.X) && self.matchSyntax(p.Index, e.Index)
+	case *ast.IndexListExpr:
+		var e, ok = expr.(*ast.IndexListExpr)
+		if !ok || len(p.Indices) != len(e.Indices) || !self.matchSyntax(p.X, e.X) {
+			return false
+		}
+		for i := range p.Indices {
+			if !self.matchSyntax(p.Indices[i], e.Indices[i]) {
+				return false
+			}
+		}
+		return true
+	case *ast.StarExpr:
+		var e, ok = expr.(*ast.StarExpr)
+		return ok && self.matchSyntax(p.X, e.X)
+	case *ast.Ellipsis:
+		var e, ok = expr.(*ast.Ellipsis)
+		return ok && self.matchSyntax(p.Elt, e.Elt)
+	case *ast.ArrayType:
+		var e, ok = expr.(*ast.ArrayType)
+		return ok && (p.Len == nil) == (e.Len == nil) &&
+			(p.Len == nil || types.ExprString(p.Len) == types.ExprString(e.Len)) &&
+			self.matchSyntax(p.Elt, e.Elt)
+	case *ast.MapType:
+		var e, ok = expr.(*ast.MapType)
+		return ok && self.matchSyntax(p.Key, e.Key) && self.matchSyntax(p.Value, e.Value)
+	case *ast.ChanType:
+		var e, ok = expr.(*ast.ChanType)
+		return ok && p.Dir == e.Dir && self.matchSyntax(p.Value, e.Value)
+	case *ast.FuncType:
+		var e, ok = expr.(*ast.FuncType)
+		if !ok {
+			return false
+		}
+		var (
+			pp, ep = patternTypes(p.Params), patternTypes(e.Params)
+			pr, er = patternTypes(p.Results), patternTypes(e.Results)
+		)
+		if len(pp) != len(ep) || len(pr) != len(er) {
+			return false
+		}
+		for i := range pp {
+			if !self.matchSyntax(pp[i], ep[i]) {
+				return false
+			}
+		}
+		for i := range pr {
+			if !self.matchSyntax(pr[i], er[i]) {
+				return false
+			}
+		}
+		return true
+	case *ast.InterfaceType:
+		if len(p.Methods.List) > 0 {
+			return false
+		}
+		if e, ok := expr.(*ast.InterfaceType); ok {
+			return len(e.Methods.List) == 0
+		}
+		var e, ok = expr.(*ast.Ident)
+		return ok && e.Name == "any"
+	}
+
+	return false
+}
+
+// patternTypes returns the type of each param or result in fields,
+// repeated for each name of a field.
+func patternTypes(fields *ast.FieldList) (out []ast.Expr) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		for i := 0; i < max(1, len(field.Names)); i++ {
+			out = append(out, field.Type)
+		}
+	}
+	return
+}
+
+// parseTypeExpr parses type expression typ as declared in a param or
+// result list, returning nil if typ is not a valid type expression.
+func parseTypeExpr(typ string) ast.Expr {
+	if elt, ok := strings.CutPrefix(typ, "..."); ok {
+		if expr := parseTypeExpr(elt); expr != nil {
+			return &ast.Ellipsis{Elt: expr}
+		}
+		return nil
+	}
+	var expr, err = parser.ParseExpr(typ)
+	if err != nil {
+		return nil
+	}
+	return expr
+}
+
+// isWildcard returns true if name is a signature pattern wildcard.
+func isWildcard(name string) bool {
+	if name == "_" {
+		return true
+	}
+	for i, r := range name {
+		if (i == 0 && !unicode.IsUpper(r)) || (i > 0 && !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
@@ -0,0 +1,78 @@
+package bast
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFuncsMatching tests signature pattern matching
+func TestFuncsMatching(t *testing.T) {
+	names := func(decls []Declaration) string {
+		var out []string
+		for _, decl := range decls {
+			switch d := decl.(type) {
+			case *Func:
+				out = append(out, d.Name)
+			case *Method:
+				var recv = "Service"
+				if d.Receiver != nil {
+					recv = strings.TrimPrefix(d.Receiver.Type, "*")
+				}
+				out = append(out, recv+"."+d.Name)
+			}
+		}
+		return strings.Join(out, " ")
+	}
+
+	tests := []struct {
+		sig      string
+		expected string
+	}{
+		{"func(http.ResponseWriter, *http.Request)", "ServeIndex ServeUser"},
+		{"func(context.Context, *T) (*U, error)", "Service.GetUser CreateUser AddUser Server.GetUser"},
+		{"func(context.Context, *T) (*T, error)", "CreateUser"},
+		{"func(ctx context.Context, user *User) (*Group, error)", "Service.GetUser AddUser Server.GetUser"},
+		{"func(context.Context, _) (T, error)", "Service.GetUser CreateUser AddUser Value Server.GetUser"},
+		{"func(string, ...*User) string", "Join"},
+		{"func(string, []*User) string", ""},
+	}
+
+	// Expected names are scoped to the handlers package, so it is loaded on
+	// its own, once per type checking mode.
+	for _, typeChecking := range []bool{true, false} {
+		cfg := DefaultConfig()
+		cfg.Dir = "_testproject"
+		cfg.TypeChecking = typeChecking
+		bast, err := Load(cfg, "./pkg/handlers")
+		if err != nil {
+			t.Fatalf("Failed to load test project: %v", err)
+		}
+		for _, test := range tests {
+			decls, err := bast.FuncsMatching(test.sig)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.sig, err)
+			}
+			if got := names(decls); got != test.expected {
+				t.Errorf("%s (type checking %v): expected %q, got %q", test.sig, typeChecking, test.expected, got)
+			}
+		}
+	}
+
+	t.Run("InvalidPattern", func(t *testing.T) {
+		bast := loadTestProject(t, false)
+		for _, sig := range []string{"func(", "int"} {
+			if _, err := bast.FuncsMatching(sig); err == nil {
+				t.Errorf("%s: expected error", sig)
+			}
+		}
+	})
+}
+
+// TestIsWildcard tests signature pattern wildcard names
+func TestIsWildcard(t *testing.T) {
+	for name, expected := range map[string]bool{"_": true, "T": true, "U1": true, "TU": false, "t": false, "": false} {
+		if isWildcard(name) != expected {
+			t.Errorf("isWildcard(%q): expected %v", name, expected)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import "slices"
+
+// stronglyConnected returns the strongly connected components of a graph
+// of nodes with edges returned by edges that form cycles, i.e. contain more
+// than one node or a node with an edge to itself.
+//
+// Components are ordered by their first node and nodes within a component
+// are ordered as in nodes. Edges to nodes not in nodes are followed.
+func stronglyConnected[T comparable](nodes []T, edges func(node T) []T) (out [][]T) {
+
+	// Tarjan's strongly connected components.
+	var (
+		index   = make(map[T]int)
+		lowlink = make(map[T]int)
+		onStack = make(map[T]bool)
+		order   = make(map[T]int)
+		stack   []T
+		connect func(node T)
+	)
+	for i, node := range nodes {
+		if _, exists := order[node]; !exists {
+			order[node] = i
+		}
+	}
+	connect = func(node T) {
+		index[node] = len(index)
+		lowlink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+		var selfLoop bool
+		for _, to := range edges(node) {
+			if to == node {
+				selfLoop = true
+			}
+			if _, visited := index[to]; !visited {
+				connect(to)
+				lowlink[node] = min(lowlink[node], lowlink[to])
+			} else if onStack[to] {
+				lowlink[node] = min(lowlink[node], index[to])
+			}
+		}
+		if lowlink[node] != index[node] {
+			return
+		}
+		var scc []T
+		for {
+			var top = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			scc = append(scc, top)
+			if top == node {
+				break
+			}
+		}
+		if len(scc) > 1 || selfLoop {
+			out = append(out, scc)
+		}
+	}
+	for _, node := range nodes {
+		if _, visited := index[node]; !visited {
+			connect(node)
+		}
+	}
+
+	var rank = func(node T) int {
+		if i, ok := order[node]; ok {
+			return i
+		}
+		return len(nodes)
+	}
+	for _, scc := range out {
+		slices.SortStableFunc(scc, func(a, b T) int { return rank(a) - rank(b) })
+	}
+	slices.SortStableFunc(out, func(a, b []T) int { return rank(a[0]) - rank(b[0]) })
+
+	return
+}
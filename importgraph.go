@@ -0,0 +1,266 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ImportClass specifies the origin of an imported package relative to the
+// importing package.
+type ImportClass int
+
+const (
+	// ImportStdlib is an import of a standard library package.
+	ImportStdlib ImportClass = iota
+	// ImportModule is an import of a package from the same module as the
+	// importing package.
+	ImportModule
+	// ImportThirdParty is an import of a package from another module.
+	ImportThirdParty
+)
+
+// String implements fmt.Stringer.
+func (self ImportClass) String() string {
+	switch self {
+	case ImportStdlib:
+		return "stdlib"
+	case ImportModule:
+		return "module"
+	case ImportThirdParty:
+		return "third party"
+	}
+	return "invalid"
+}
+
+// ImportEdge is an import of a package by a loaded package.
+type ImportEdge struct {
+	// From is the import path of the importing package.
+	From string
+	// To is the import path of the imported package.
+	To string
+	// Files is the number of files in From importing To.
+	Files int
+	// Class is the origin of To relative to From.
+	Class ImportClass
+}
+
+// ImportGraph is a package import graph of loaded packages.
+//
+// Test variant packages are not included.
+type ImportGraph struct {
+	// Nodes are import paths of loaded packages in load order followed by
+	// import paths of packages they import in order of first import.
+	Nodes []string
+	// Edges are the imports of loaded packages in load order and in order
+	// of first import within a package.
+	Edges []*ImportEdge
+
+	imports    map[string][]*ImportEdge
+	importedBy map[string][]*ImportEdge
+}
+
+// ImportGraph builds an import graph from file imports of loaded packages.
+//
+// Imports of the "C" pseudo package are not included.
+func (self *Bast) ImportGraph() *ImportGraph {
+
+	var (
+		out = &ImportGraph{
+			imports:    make(map[string][]*ImportEdge),
+			importedBy: make(map[string][]*ImportEdge),
+		}
+		seen = make(map[string]bool)
+		pkgs []*Package
+	)
+
+	for _, pkg := range self.packages.Values() {
+		if pkg.TestVariantOf != "" || seen[pkg.Path] {
+			continue
+		}
+		seen[pkg.Path] = true
+		out.Nodes = append(out.Nodes, pkg.Path)
+		pkgs = append(pkgs, pkg)
+	}
+
+	for _, pkg := range pkgs {
+		var (
+			module string
+			edges  = make(map[string]*ImportEdge)
+		)
+		if pkg.pkg != nil && pkg.pkg.Module != nil {
+			module = pkg.pkg.Module.Path
+		}
+		for _, file := range pkg.Files.Values() {
+			for _, imp := range file.Imports.Values() {
+				if imp.Path == "C" {
+					continue
+				}
+				if edge, ok := edges[imp.Path]; ok {
+					edge.Files++
+					continue
+				}
+				var edge = &ImportEdge{
+					From:  pkg.Path,
+					To:    imp.Path,
+					Files: 1,
+					Class: classifyImport(module, imp.Path),
+				}
+				edges[imp.Path] = edge
+				out.Edges = append(out.Edges, edge)
+				out.imports[edge.From] = append(out.imports[edge.From], edge)
+				out.importedBy[edge.To] = append(out.importedBy[edge.To], edge)
+				if !seen[edge.To] {
+					seen[edge.To] = true
+					out.Nodes = append(out.Nodes, edge.To)
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+// Imports returns the imports of package with import path pkgPath.
+func (self *ImportGraph) Imports(pkgPath string) []*ImportEdge { return self.imports[pkgPath] }
+
+// ImportedBy returns the imports of package with import path pkgPath by
+// loaded packages.
+func (self *ImportGraph) ImportedBy(pkgPath string) []*ImportEdge { return self.importedBy[pkgPath] }
+
+// Cycles returns import cycles as groups of mutually dependent packages,
+// each group in node order, or nil if the graph is acyclic.
+func (self *ImportGraph) Cycles() [][]string {
+	return stronglyConnected(self.Nodes, func(node string) (out []string) {
+		for _, edge := range self.imports[node] {
+			out = append(out, edge.To)
+		}
+		return
+	})
+}
+
+// TopologicalOrder returns the graph nodes ordered so that each package
+// follows all packages it imports, keeping node order where possible.
+//
+// It returns an error if the graph contains an import cycle.
+func (self *ImportGraph) TopologicalOrder() ([]string, error) {
+	if cycles := self.Cycles(); len(cycles) > 0 {
+		return nil, fmt.Errorf("import cycle: %s", strings.Join(cycles[0], ", "))
+	}
+	var (
+		out  = make([]string, 0, len(self.Nodes))
+		done = make(map[string]bool)
+		add  func(node string)
+	)
+	add = func(node string) {
+		if done[node] {
+			return
+		}
+		done[node] = true
+		for _, edge := range self.imports[node] {
+			add(edge.To)
+		}
+		out = append(out, node)
+	}
+	for _, node := range self.Nodes {
+		add(node)
+	}
+	return out, nil
+}
+
+// WriteDOT writes the graph to w in Graphviz DOT format.
+//
+// Edges are labeled with the number of importing files and imports other
+// than same module imports are drawn dashed.
+func (self *ImportGraph) WriteDOT(w io.Writer) (err error) {
+	var p = func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	p("digraph imports {\n")
+	for _, node := range self.Nodes {
+		p("\t%s;\n", strconv.Quote(node))
+	}
+	for _, edge := range self.Edges {
+		var style string
+		if edge.Class != ImportModule {
+			style = ", style=dashed"
+		}
+		p("\t%s -> %s [label=%d%s];\n", strconv.Quote(edge.From), strconv.Quote(edge.To), edge.Files, style)
+	}
+	p("}\n")
+	return
+}
+
+// WriteMermaid writes the graph to w as a Mermaid flowchart.
+//
+// Nodes are identified by their index in Nodes and labeled by import path.
+// Edges are labeled with the number of importing files.
+func (self *ImportGraph) WriteMermaid(w io.Writer) (err error) {
+	var p = func(format string, args ...any) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	var ids = make(map[string]int, len(self.Nodes))
+	p("graph LR\n")
+	for i, node := range self.Nodes {
+		ids[node] = i
+		p("\tn%d[%s]\n", i, strconv.Quote(node))
+	}
+	for _, edge := range self.Edges {
+		p("\tn%d -->|%d| n%d\n", ids[edge.From], edge.Files, ids[edge.To])
+	}
+	return
+}
+
+// MarshalJSON implements json.Marshaler.
+func (self *ImportGraph) MarshalJSON() ([]byte, error) {
+
+	type jsonEdge struct {
+		From  string `json:"from"`
+		To    string `json:"to"`
+		Files int    `json:"files"`
+		Class string `json:"class"`
+	}
+	var out struct {
+		Nodes []string   `json:"nodes"`
+		Edges []jsonEdge `json:"edges"`
+	}
+
+	out.Nodes = append(make([]string, 0, len(self.Nodes)), self.Nodes...)
+	out.Edges = make([]jsonEdge, 0, len(self.Edges))
+	for _, edge := range self.Edges {
+		out.Edges = append(out.Edges, jsonEdge{
+			From:  edge.From,
+			To:    edge.To,
+			Files: edge.Files,
+			Class: edge.Class.String(),
+		})
+	}
+
+	return json.Marshal(out)
+}
+
+// classifyImport returns the class of import path imp imported from a
+// package of module, which may be empty if unknown.
+//
+// Like the go command, paths whose first element contains no dot are
+// considered standard library packages.
+func classifyImport(module, imp string) ImportClass {
+	if module != "" && (imp == module || strings.HasPrefix(imp, module+"/")) {
+		return ImportModule
+	}
+	var first, _, _ = strings.Cut(imp, "/")
+	if !strings.Contains(first, ".") {
+		return ImportStdlib
+	}
+	return ImportThirdParty
+}
@@ -0,0 +1,166 @@
+package bast
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestImportGraph tests building and exporting of the import graph
+func TestImportGraph(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	const (
+		prefix       = "github.com/vedranvuk/bast/_testproject/pkg/"
+		callsPath    = prefix + "calls"
+		crosspkgPath = prefix + "crosspkg"
+		modelsPath   = prefix + "models"
+		typesPath    = prefix + "types"
+	)
+
+	graph := bast.ImportGraph()
+
+	t.Run("Nodes", func(t *testing.T) {
+		for _, path := range []string{callsPath, crosspkgPath, modelsPath, typesPath, "math", "context", prefix + "generics"} {
+			if !slices.Contains(graph.Nodes, path) {
+				t.Errorf("Expected node %s", path)
+			}
+		}
+		for _, path := range graph.Nodes {
+			if strings.Contains(path, ".test") || strings.HasSuffix(path, "_test") {
+				t.Errorf("Expected no test variant nodes, got %s", path)
+			}
+		}
+	})
+
+	t.Run("Edges", func(t *testing.T) {
+		imports := graph.Imports(callsPath)
+		if len(imports) != 1 || imports[0].To != "math" || imports[0].Files != 2 || imports[0].Class != ImportStdlib {
+			t.Errorf("Expected calls to import math from 2 files, got %+v", imports)
+		}
+
+		var toTypes *ImportEdge
+		for _, edge := range graph.Imports(crosspkgPath) {
+			if edge.To == typesPath {
+				toTypes = edge
+			}
+		}
+		if toTypes == nil || toTypes.Files != 1 || toTypes.Class != ImportModule {
+			t.Errorf("Expected a single same module import of types from crosspkg, got %+v", toTypes)
+		}
+
+		var importers []string
+		for _, edge := range graph.ImportedBy(typesPath) {
+			importers = append(importers, edge.From)
+		}
+		if !slices.Contains(importers, crosspkgPath) || !slices.Contains(importers, modelsPath) {
+			t.Errorf("Expected crosspkg and models to import types, got %v", importers)
+		}
+	})
+
+	t.Run("TopologicalOrder", func(t *testing.T) {
+		if cycles := graph.Cycles(); cycles != nil {
+			t.Errorf("Expected no cycles, got %v", cycles)
+		}
+		order, err := graph.TopologicalOrder()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(order) != len(graph.Nodes) {
+			t.Fatalf("Expected %d nodes in order, got %d", len(graph.Nodes), len(order))
+		}
+		var (
+			types    = slices.Index(order, typesPath)
+			models   = slices.Index(order, modelsPath)
+			crosspkg = slices.Index(order, crosspkgPath)
+		)
+		if types > models || models > crosspkg {
+			t.Errorf("Expected types before models before crosspkg, got %v", order)
+		}
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := graph.WriteDOT(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if expected := `"` + callsPath + `" -> "math" [label=2, style=dashed];`; !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected DOT output to contain %q:\n%s", expected, buf.String())
+		}
+
+		buf.Reset()
+		if err := graph.WriteMermaid(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(buf.String(), "graph LR\n") || !strings.Contains(buf.String(), " -->|2| n") {
+			t.Errorf("Unexpected Mermaid output:\n%s", buf.String())
+		}
+
+		data, err := json.Marshal(graph)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out struct {
+			Nodes []string
+			Edges []struct {
+				From, To string
+				Files    int
+				Class    string
+			}
+		}
+		if err := json.Unmarshal(data, &out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out.Nodes, graph.Nodes) || len(out.Edges) != len(graph.Edges) {
+			t.Fatalf("Unexpected JSON graph: %s", data)
+		}
+		for i, edge := range out.Edges {
+			if edge.From != graph.Edges[i].From || edge.Class != graph.Edges[i].Class.String() {
+				t.Errorf("Unexpected JSON edge %d: %+v", i, edge)
+			}
+		}
+	})
+}
+
+// TestImportGraphCycles tests cycle detection on a synthetic graph
+func TestImportGraphCycles(t *testing.T) {
+	graph := &ImportGraph{
+		Nodes:   []string{"a", "b", "c", "d", "e"},
+		imports: make(map[string][]*ImportEdge),
+	}
+	for _, edge := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}, {"c", "d"}, {"e", "e"}} {
+		graph.imports[edge[0]] = append(graph.imports[edge[0]], &ImportEdge{From: edge[0], To: edge[1]})
+	}
+
+	expected := [][]string{{"a", "b", "c"}, {"e"}}
+	if cycles := graph.Cycles(); !reflect.DeepEqual(cycles, expected) {
+		t.Errorf("Expected cycles %v, got %v", expected, cycles)
+	}
+	if _, err := graph.TopologicalOrder(); err == nil {
+		t.Error("Expected an error for cyclic graph")
+	}
+}
+
+// TestClassifyImport tests import classification
+func TestClassifyImport(t *testing.T) {
+	tests := []struct {
+		module, imp string
+		expected    ImportClass
+	}{
+		{"example.com/mod", "fmt", ImportStdlib},
+		{"example.com/mod", "net/http", ImportStdlib},
+		{"example.com/mod", "example.com/mod", ImportModule},
+		{"example.com/mod", "example.com/mod/sub", ImportModule},
+		{"example.com/mod", "example.com/module", ImportThirdParty},
+		{"example.com/mod", "github.com/user/repo", ImportThirdParty},
+		{"", "github.com/user/repo", ImportThirdParty},
+	}
+	for _, test := range tests {
+		if class := classifyImport(test.module, test.imp); class != test.expected {
+			t.Errorf("classifyImport(%q, %q): expected %s, got %s", test.module, test.imp, test.expected, class)
+		}
+	}
+}
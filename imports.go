@@ -0,0 +1,261 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"go/token"
+	"go/types"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Imports manages imports of a file being generated.
+//
+// Generators record packages they reference using [Imports.Use] or
+// [Imports.Qualifier], which pick a qualifier that does not collide with
+// other imports, reserved identifiers or reserved local names, and render
+// the import block of used packages using [Imports.WriteTo] or
+// [Imports.String].
+//
+// Imports implements [ImportContext].
+type Imports struct {
+	// pkgPath is the import path of the package of the generated file.
+	pkgPath string
+	// entries are the imports keyed by import path.
+	entries map[string]*importEntry
+	// names maps names in use to import paths, empty path for reserved
+	// names.
+	names map[string]string
+}
+
+// importEntry is an import managed by Imports.
+type importEntry struct {
+	// path is the import path.
+	path string
+	// name is the name qualifying identifiers of the package, "." for dot
+	// imports and "_" for blank imports.
+	name string
+	// pkgName is the package name of the imported package.
+	pkgName string
+	// used is true if the import is used.
+	used bool
+}
+
+// NewImports returns new Imports for a file of the package with import path
+// pkgPath.
+func NewImports(pkgPath string) *Imports {
+	return &Imports{
+		pkgPath: pkgPath,
+		entries: make(map[string]*importEntry),
+		names:   make(map[string]string),
+	}
+}
+
+// Reserve reserves names, e.g. names of declarations of the generated
+// package, so they are not used as import qualifiers.
+func (self *Imports) Reserve(names ...string) {
+	for _, name := range names {
+		if _, exists := self.names[name]; !exists {
+			self.names[name] = ""
+		}
+	}
+}
+
+// Add adds an import of pkgPath without marking it used and returns the
+// name qualifying identifiers of the package. If the package was already
+// added its name is returned.
+//
+// Unused imports are not rendered.
+func (self *Imports) Add(pkgPath string) string {
+	return self.add(pkgPath, "", importBase(pkgPath)).name
+}
+
+// AddNamed adds an import of pkgPath named name without marking it used.
+// Name may be "." for a dot import or "_" for a blank import, which is
+// always rendered. If name is taken a non-colliding name is picked.
+//
+// It returns the name qualifying identifiers of the package.
+func (self *Imports) AddNamed(name, pkgPath string) string {
+	var entry = self.add(pkgPath, name, importBase(pkgPath))
+	if name == "_" {
+		entry.used = true
+	}
+	return entry.name
+}
+
+// AddFile adds imports of file without marking them used, keeping their
+// names, e.g. to carry over imports of a file code is copied from.
+func (self *Imports) AddFile(file *File) {
+	for _, imp := range file.Imports.Values() {
+		var entry = self.add(imp.Path, imp.Name, file.importName(&ImportSpec{Path: imp.Path}))
+		if imp.Name == "_" {
+			entry.used = true
+		}
+	}
+}
+
+// Use adds an import of pkgPath if not added, marks it used and returns the
+// name qualifying identifiers of the package.
+func (self *Imports) Use(pkgPath string) string {
+	var entry = self.add(pkgPath, "", importBase(pkgPath))
+	entry.used = true
+	return entry.name
+}
+
+// Qualifier implements [ImportContext]. It returns an empty string for the
+// package of the file and dot imports and otherwise uses the import as
+// [Imports.Use].
+func (self *Imports) Qualifier(pkgPath string) string {
+	if pkgPath == self.pkgPath {
+		return ""
+	}
+	if name := self.Use(pkgPath); name != "." {
+		return name
+	}
+	return ""
+}
+
+// Paths returns import paths of used imports, sorted.
+func (self *Imports) Paths() (out []string) {
+	for _, entry := range self.used() {
+		out = append(out, entry.path)
+	}
+	return
+}
+
+// WriteTo writes the import declaration of used imports to w, standard
+// library imports first, each group sorted by import path. Names are
+// written only if they differ from the package name.
+//
+// It writes nothing if no imports are used.
+func (self *Imports) WriteTo(w io.Writer) (int64, error) {
+	var n, err = io.WriteString(w, self.String())
+	return int64(n), err
+}
+
+// String returns the import declaration written by [Imports.WriteTo].
+func (self *Imports) String() string {
+
+	var entries = self.used()
+	switch len(entries) {
+	case 0:
+		return ""
+	case 1:
+		return "import " + entries[0].spec() + "\n"
+	}
+
+	var (
+		buf  strings.Builder
+		prev = isStdlib(entries[0].path)
+	)
+	buf.WriteString("import (\n")
+	for _, entry := range entries {
+		if std := isStdlib(entry.path); std != prev {
+			buf.WriteString("\n")
+			prev = std
+		}
+		buf.WriteString("\t" + entry.spec() + "\n")
+	}
+	buf.WriteString(")\n")
+
+	return buf.String()
+}
+
+// add adds an import of pkgPath named name, picking a name derived from
+// pkgName if name is empty or taken, and returns its entry. If pkgPath was
+// already added the existing entry is returned.
+func (self *Imports) add(pkgPath, name, pkgName string) *importEntry {
+
+	if entry, ok := self.entries[pkgPath]; ok {
+		return entry
+	}
+
+	var entry = &importEntry{path: pkgPath, pkgName: pkgName}
+	switch name {
+	case ".", "_":
+		entry.name = name
+	default:
+		if name == "" {
+			name = pkgName
+		}
+		entry.name = self.uniqueName(importIdent(name))
+		self.names[entry.name] = pkgPath
+	}
+	self.entries[pkgPath] = entry
+
+	return entry
+}
+
+// uniqueName returns name or name followed by a number if name is taken,
+// reserved or a predeclared identifier.
+func (self *Imports) uniqueName(name string) string {
+	var available = func(s string) bool {
+		if _, taken := self.names[s]; taken {
+			return false
+		}
+		return !token.IsKeyword(s) && types.Universe.Lookup(s) == nil
+	}
+	if available(name) {
+		return name
+	}
+	for i := 2; ; i++ {
+		if s := name + strconv.Itoa(i); available(s) {
+			return s
+		}
+	}
+}
+
+// used returns used entries sorted by standard library first, then by
+// import path.
+func (self *Imports) used() (out []*importEntry) {
+	for _, entry := range self.entries {
+		if entry.used {
+			out = append(out, entry)
+		}
+	}
+	slices.SortFunc(out, func(a, b *importEntry) int {
+		if sa, sb := isStdlib(a.path), isStdlib(b.path); sa != sb {
+			if sa {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.path, b.path)
+	})
+	return
+}
+
+// spec returns the import spec of the entry.
+func (self *importEntry) spec() string {
+	if self.name == self.pkgName {
+		return strconv.Quote(self.path)
+	}
+	return self.name + " " + strconv.Quote(self.path)
+}
+
+// isStdlib returns true if pkgPath is the import path of a standard library
+// package, i.e. its first element contains no dot.
+func isStdlib(pkgPath string) bool {
+	var first, _, _ = strings.Cut(pkgPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// importIdent returns name with characters not valid in an identifier
+// removed, or "pkg" if no valid characters remain.
+func importIdent(name string) string {
+	var out = strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name)
+	if out == "" || unicode.IsDigit(rune(out[0])) {
+		return "pkg" + out
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+package bast
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+)
+
+// TestImports tests the Imports builder
+func TestImports(t *testing.T) {
+
+	t.Run("Names", func(t *testing.T) {
+		imports := NewImports("example.com/app/gen")
+		imports.Reserve("model")
+
+		tests := []struct {
+			path     string
+			expected string
+		}{
+			{"strings", "strings"},
+			{"github.com/go-chi/chi/v5", "chi"},
+			{"gopkg.in/yaml.v3", "yaml"},
+			{"example.com/api/v1", "api"},
+			{"example.com/api/v2", "api2"},
+			{"example.com/app/model", "model2"},
+			{"example.com/other/model", "model3"},
+			{"example.com/app/string", "string2"},
+			{"example.com/app/go-kit", "gokit"},
+			{"example.com/app/type", "type2"},
+			{"example.com/app/123", "pkg123"},
+			{"strings", "strings"},
+		}
+		for _, test := range tests {
+			if name := imports.Use(test.path); name != test.expected {
+				t.Errorf("Use(%q) = %q, expected %q", test.path, name, test.expected)
+			}
+		}
+		if q := imports.Qualifier("example.com/app/gen"); q != "" {
+			t.Errorf("Qualifier of own package = %q, expected empty", q)
+		}
+	})
+
+	t.Run("String", func(t *testing.T) {
+		imports := NewImports("example.com/app/gen")
+		imports.Reserve("model")
+		imports.Use("github.com/go-chi/chi/v5")
+		imports.Use("strings")
+		imports.Use("example.com/app/model")
+		imports.Add("fmt")
+		imports.AddNamed("_", "embed")
+		imports.AddNamed(".", "example.com/app/dsl")
+		imports.Qualifier("example.com/app/dsl")
+		imports.Use("context")
+
+		expected := `import (
+	"context"
+	_ "embed"
+	"strings"
+
+	. "example.com/app/dsl"
+	model2 "example.com/app/model"
+	"github.com/go-chi/chi/v5"
+)
+`
+		if s := imports.String(); s != expected {
+			t.Errorf("String() =\n%s\nexpected\n%s", s, expected)
+		}
+
+		var buf bytes.Buffer
+		if _, err := imports.WriteTo(&buf); err != nil || buf.String() != expected {
+			t.Errorf("WriteTo wrote %q, %v", buf.String(), err)
+		}
+
+		paths := []string{"context", "embed", "strings", "example.com/app/dsl", "example.com/app/model", "github.com/go-chi/chi/v5"}
+		if p := imports.Paths(); !slices.Equal(p, paths) {
+			t.Errorf("Paths() = %v, expected %v", p, paths)
+		}
+	})
+
+	t.Run("SingleAndEmpty", func(t *testing.T) {
+		imports := NewImports("example.com/app/gen")
+		imports.Add("fmt")
+		if s := imports.String(); s != "" {
+			t.Errorf("expected empty import block, got %q", s)
+		}
+		imports.Use("gopkg.in/yaml.v3")
+		if s := imports.String(); s != "import \"gopkg.in/yaml.v3\"\n" {
+			t.Errorf("unexpected single import %q", s)
+		}
+	})
+
+	t.Run("AddFile", func(t *testing.T) {
+		file := NewFile(nil, "file.go")
+		file.Imports.Put("strings", NewImport("str", "strings"))
+		file.Imports.Put("github.com/go-chi/chi/v5", NewImport("", "github.com/go-chi/chi/v5"))
+		file.Imports.Put("embed", NewImport("_", "embed"))
+
+		imports := NewImports("example.com/app/gen")
+		imports.AddFile(file)
+		if name := imports.Use("strings"); name != "str" {
+			t.Errorf("expected alias str, got %q", name)
+		}
+		if name := imports.Add("example.com/other/chi"); name != "chi2" {
+			t.Errorf("expected chi2, got %q", name)
+		}
+
+		expected := `import (
+	_ "embed"
+	str "strings"
+)
+`
+		if s := imports.String(); s != expected {
+			t.Errorf("String() =\n%s\nexpected\n%s", s, expected)
+		}
+	})
+
+	t.Run("QualifyType", func(t *testing.T) {
+		bast := loadTestProject(t, false)
+		from := bast.PkgStruct("github.com/vedranvuk/bast/_testproject/pkg/models", "TestStruct1").GetFile()
+
+		imports := NewImports("example.com/app/gen")
+		imports.Reserve("models")
+		out, err := bast.QualifyType("map[string]*TestStruct1", from, imports)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != "map[string]*models2.TestStruct1" {
+			t.Errorf("unexpected qualified type %q", out)
+		}
+		expected := "import models2 \"github.com/vedranvuk/bast/_testproject/pkg/models\"\n"
+		if s := imports.String(); s != expected {
+			t.Errorf("String() = %q, expected %q", s, expected)
+		}
+	})
+}
+
+// TestImportSpecAssumedName tests version suffix handling of import paths
+func TestImportSpecAssumedName(t *testing.T) {
+	file := NewFile(nil, "file.go")
+	file.Imports.Put("github.com/go-chi/chi/v5", NewImport("", "github.com/go-chi/chi/v5"))
+	file.Imports.Put("gopkg.in/yaml.v3", NewImport("", "gopkg.in/yaml.v3"))
+	file.Imports.Put("example.com/api/v1", NewImport("", "example.com/api/v1"))
+	v := NewVar(file, "x", "chi.Router")
+
+	tests := []struct {
+		selector string
+		expected string
+	}{
+		{"chi.Router", "github.com/go-chi/chi/v5"},
+		{"yaml.Node", "gopkg.in/yaml.v3"},
+		{"api.Client", "example.com/api/v1"},
+		{"v5.Router", ""},
+		{"v1.Client", ""},
+	}
+	for _, test := range tests {
+		imp := v.ImportSpecBySelectorExpr(test.selector)
+		var got string
+		if imp != nil {
+			got = imp.Path
+		}
+		if got != test.expected {
+			t.Errorf("ImportSpecBySelectorExpr(%q) = %q, expected %q", test.selector, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import "strings"
+
+// DeclKind is the kind of a top-level declaration.
+type DeclKind int
+
+const (
+	// DeclInvalid is an invalid declaration kind.
+	DeclInvalid DeclKind = iota
+	// DeclVar is a [Var] declaration.
+	DeclVar
+	// DeclConst is a [Const] declaration.
+	DeclConst
+	// DeclFunc is a [Func] declaration.
+	DeclFunc
+	// DeclMethod is a [Method] declaration.
+	DeclMethod
+	// DeclType is a [Type] declaration.
+	DeclType
+	// DeclStruct is a [Struct] declaration.
+	DeclStruct
+	// DeclInterface is an [Interface] declaration.
+	DeclInterface
+)
+
+// String implements fmt.Stringer.
+func (self DeclKind) String() string {
+	switch self {
+	case DeclVar:
+		return "var"
+	case DeclConst:
+		return "const"
+	case DeclFunc:
+		return "func"
+	case DeclMethod:
+		return "method"
+	case DeclType:
+		return "type"
+	case DeclStruct:
+		return "struct"
+	case DeclInterface:
+		return "interface"
+	}
+	return "invalid"
+}
+
+// kindOf returns the kind of decl.
+func kindOf(decl any) DeclKind {
+	switch decl.(type) {
+	case *Var:
+		return DeclVar
+	case *Const:
+		return DeclConst
+	case *Func:
+		return DeclFunc
+	case *Method:
+		return DeclMethod
+	case *Type:
+		return DeclType
+	case *Struct:
+		return DeclStruct
+	case *Interface:
+		return DeclInterface
+	}
+	return DeclInvalid
+}
+
+// kindOfType returns the kind of declarations of type T.
+func kindOfType[T declarations]() DeclKind {
+	var zero T
+	return kindOf(zero)
+}
+
+// declIndex indexes top-level declarations of a package.
+type declIndex struct {
+	// names maps declaration names to the first declaration of that name
+	// in parse order. Methods are not package level names and are indexed
+	// in methods instead.
+	names map[string]Declaration
+	// methods maps method names to methods of that name in parse order.
+	methods map[string][]*Method
+	// kinds maps declaration kinds to declarations of that kind in parse
+	// order.
+	kinds map[DeclKind][]Declaration
+	// receivers maps receiver type names to their methods in parse order.
+	receivers map[string][]*Method
+	// constructors maps type names to funcs constructing them in parse
+	// order, see [Func.Constructs].
+	constructors map[string][]*Func
+}
+
+// newDeclIndex returns a new index of declarations of pkg.
+func newDeclIndex(pkg *Package) *declIndex {
+	var out = &declIndex{
+		names:        make(map[string]Declaration),
+		methods:      make(map[string][]*Method),
+		kinds:        make(map[DeclKind][]Declaration),
+		receivers:    make(map[string][]*Method),
+		constructors: make(map[string][]*Func),
+	}
+	for _, file := range pkg.Files.Values() {
+		for _, name := range file.Declarations.Keys() {
+			var decl, _ = file.Declarations.Get(name)
+			var kind = kindOf(decl)
+			out.kinds[kind] = append(out.kinds[kind], decl)
+			if m, ok := decl.(*Method); ok {
+				out.methods[name] = append(out.methods[name], m)
+				if m.Receiver != nil {
+					var recv = strings.TrimLeft(m.Receiver.Type, "*")
+					out.receivers[recv] = append(out.receivers[recv], m)
+				}
+				continue
+			}
+			if _, exists := out.names[name]; !exists {
+				out.names[name] = decl
+			}
+		}
+	}
+	for _, decl := range out.kinds[DeclFunc] {
+		var fn = decl.(*Func)
+		if fn.isType {
+			continue
+		}
+		if typeName := constructs(fn, out.names); typeName != "" {
+			out.constructors[typeName] = append(out.constructors[typeName], fn)
+		}
+	}
+	return out
+}
+
+// Reindex rebuilds the declaration indexes of all packages.
+//
+// Indexes are built by [Parser.Parse]. Reindex must be called after files
+// or declarations of parsed packages are added or removed for methods such
+// as Pkg*, Any*, MethodSet and FieldNames to reflect the changes. It must
+// not be called concurrently with other methods.
+func (self *Bast) Reindex() {
+	for _, pkg := range self.packages.Values() {
+		pkg.idx = newDeclIndex(pkg)
+	}
+	self.names = newNameIndex(self)
+}
+
+// index returns the declaration index of the package.
+//
+// Packages not indexed by [Parser.Parse] or [Bast.Reindex], e.g.
+// constructed by hand, are scanned on each call.
+func (self *Package) index() *declIndex {
+	if self.idx != nil {
+		return self.idx
+	}
+	return newDeclIndex(self)
+}
+
+// nameIndex returns the map of declaration names to declarations of that
+// name, at most one per package, in load order.
+//
+// A Bast not indexed by [Parser.Parse] or [Bast.Reindex] is scanned on
+// each call.
+func (self *Bast) nameIndex() map[string][]Declaration {
+	if self.names != nil {
+		return self.names
+	}
+	return newNameIndex(self)
+}
+
+// newNameIndex returns the map of declaration names to declarations of that
+// name, at most one per package of b, in load order. Methods and
+// declarations in copies of files of a loaded package under test are not
+// included.
+func newNameIndex(b *Bast) map[string][]Declaration {
+	var out = make(map[string][]Declaration)
+	for _, pkg := range b.packages.Values() {
+		for name, decl := range pkg.index().names {
+			if !pkg.isCopy(decl.GetFile()) {
+				out[name] = append(out[name], decl)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,245 @@
+package bast
+
+import (
+	"fmt"
+	"testing"
+)
+
+// syntheticBast returns an indexed Bast with a single synthetic package
+// containing n files, each declaring a struct with fields, methods and a
+// constructor.
+func syntheticBast(n int) *Bast {
+	var (
+		bast = new()
+		pkg  = NewPackage("large", "example.com/large", nil)
+	)
+	pkg.bast = bast
+	for i := 0; i < n; i++ {
+		var (
+			file = NewFile(pkg, fmt.Sprintf("file%d.go", i))
+			name = fmt.Sprintf("Struct%d", i)
+			s    = NewStruct(file, name)
+		)
+		for j := 0; j < 3; j++ {
+			var f = NewField(file, fmt.Sprintf("Field%d", j))
+			f.Type = "string"
+			s.Fields.Put(f.Name, f)
+		}
+		file.Declarations.Put(name, s)
+		file.Declarations.Put("New"+name, NewFunc(file, "New"+name))
+		for _, method := range []string{"String", "Validate", "Clone"} {
+			var m = NewMethod(file, method)
+			m.Receiver = NewField(file, "self")
+			m.Receiver.Type = name
+			m.Receiver.Pointer = true
+			file.Declarations.Put(method, m)
+		}
+		pkg.Files.Put(file.Name, file)
+	}
+	bast.packages.Put(pkg.ID, pkg)
+	bast.Reindex()
+	return bast
+}
+
+// TestDeclIndex tests indexed declaration lookups
+func TestDeclIndex(t *testing.T) {
+	bast := syntheticBast(10)
+	const pkgPath = "example.com/large"
+
+	if s := bast.AnyStruct("Struct5"); s == nil || s.Name != "Struct5" {
+		t.Error("Expected AnyStruct to find Struct5")
+	}
+	if s := bast.AnyStruct("NewStruct5"); s != nil {
+		t.Error("Expected AnyStruct to not return a func")
+	}
+	if f := bast.PkgFunc(pkgPath, "NewStruct7"); f == nil {
+		t.Error("Expected PkgFunc to find NewStruct7")
+	}
+	if m := bast.MethodSet(pkgPath, "Struct3"); len(m) != 3 || m[0].Name != "String" {
+		t.Errorf("Expected 3 methods of Struct3, got %d", len(m))
+	}
+	if m := bast.MethodSet(pkgPath, "*Struct3"); len(m) != 3 {
+		t.Errorf("Expected 3 methods of *Struct3, got %d", len(m))
+	}
+	if m := bast.PkgStruct(pkgPath, "Struct3").Methods(); len(m) != 3 {
+		t.Errorf("Expected 3 methods, got %d", len(m))
+	}
+	if m := bast.PkgMethod(pkgPath, "Validate"); m == nil || m.Receiver.Type != "Struct0" {
+		t.Error("Expected PkgMethod to find the first Validate method")
+	}
+	if m := bast.AnyMethod("Clone"); m != nil {
+		t.Error("Expected AnyMethod to not return a method declared on many receivers")
+	}
+	if bast.PackageByPath(pkgPath).HasDecl("String") {
+		t.Error("Expected methods to not be package level declarations")
+	}
+	if names := bast.FieldNames(pkgPath, "Struct1"); len(names) != 3 {
+		t.Errorf("Expected 3 field names, got %v", names)
+	}
+	if file := bast.PackageByPath(pkgPath).DeclFile("Struct9"); file != "file9.go" {
+		t.Errorf("Expected Struct9 in file9.go, got %q", file)
+	}
+	if structs := bast.PkgStructs(pkgPath); len(structs) != 10 {
+		t.Errorf("Expected 10 structs, got %d", len(structs))
+	}
+	if funcs := bast.AllFuncs(); len(funcs) != 10 {
+		t.Errorf("Expected 10 funcs, got %d", len(funcs))
+	}
+}
+
+// TestAnyAmbiguous tests that Any* lookups return nil for names declared
+// in more than one package.
+func TestAnyAmbiguous(t *testing.T) {
+	bast := syntheticBast(2)
+	var (
+		pkg  = NewPackage("other", "example.com/other", nil)
+		file = NewFile(pkg, "other.go")
+		m    = NewMethod(file, "Unique")
+	)
+	pkg.bast = bast
+	m.Receiver = NewField(file, "self")
+	m.Receiver.Type = "Struct0"
+	file.Declarations.Put("Struct0", NewStruct(file, "Struct0"))
+	file.Declarations.Put("Unique", m)
+	pkg.Files.Put(file.Name, file)
+	bast.packages.Put(pkg.ID, pkg)
+	bast.Reindex()
+
+	if s := bast.AnyStruct("Struct0"); s != nil {
+		t.Errorf("Expected nil for ambiguous Struct0, got %s", s.GetPackage().Path)
+	}
+	if s := bast.PkgStruct("example.com/other", "Struct0"); s == nil {
+		t.Error("Expected PkgStruct to find Struct0 in other package")
+	}
+	if s := bast.AnyStruct("Struct1"); s == nil {
+		t.Error("Expected AnyStruct to find unique Struct1")
+	}
+	if f := bast.AnyFunc("Struct1"); f != nil {
+		t.Error("Expected AnyFunc to not return a struct")
+	}
+	if m := bast.AnyMethod("Unique"); m == nil {
+		t.Error("Expected AnyMethod to find unique method")
+	}
+
+	shared := loadTestProject(t, false)
+	if s := shared.AnyStruct("User"); s != nil {
+		t.Errorf("Expected nil for User declared in several packages, got %s", s.GetPackage().Path)
+	}
+}
+
+// TestReindex tests that indexes reflect declarations added after load
+// once reindexed and that unindexed packages are scanned.
+func TestReindex(t *testing.T) {
+	const pkgPath = "example.com/large"
+
+	t.Run("Indexed", func(t *testing.T) {
+		bast := syntheticBast(2)
+		var (
+			pkg  = bast.PackageByPath(pkgPath)
+			file = pkg.Files.Values()[0]
+		)
+		file.Declarations.Put("Added", NewStruct(file, "Added"))
+		if bast.AnyStruct("Added") != nil {
+			t.Error("Expected added struct to not be indexed before Reindex")
+		}
+		bast.Reindex()
+		if bast.AnyStruct("Added") == nil || bast.PkgStruct(pkgPath, "Added") == nil {
+			t.Error("Expected added struct to be indexed after Reindex")
+		}
+	})
+
+	t.Run("Unindexed", func(t *testing.T) {
+		bast := syntheticBast(2)
+		var pkg = NewPackage("other", "example.com/other", nil)
+		pkg.bast = bast
+		bast.packages.Put(pkg.ID, pkg)
+		var file = NewFile(pkg, "other.go")
+		pkg.Files.Put(file.Name, file)
+		file.Declarations.Put("Other", NewStruct(file, "Other"))
+		if bast.PkgStruct("example.com/other", "Other") == nil {
+			t.Error("Expected unindexed package to be scanned")
+		}
+	})
+
+	t.Run("Parse", func(t *testing.T) {
+		bast := loadTestProject(t, false)
+		if bast.names == nil {
+			t.Fatal("Expected Parse to build the name index")
+		}
+		for _, pkg := range bast.packages.Values() {
+			if pkg.idx == nil {
+				t.Fatalf("%s: expected Parse to build the declaration index", pkg.ID)
+			}
+			var want = newDeclIndex(pkg)
+			for name, decl := range want.names {
+				if pkg.idx.names[name] != decl {
+					t.Errorf("%s: %s not indexed", pkg.ID, name)
+				}
+			}
+			for recv, methods := range want.receivers {
+				if len(pkg.idx.receivers[recv]) != len(methods) {
+					t.Errorf("%s: expected %d methods of %s, got %d", pkg.ID, len(methods), recv, len(pkg.idx.receivers[recv]))
+				}
+			}
+		}
+		const path = "github.com/vedranvuk/bast/_testproject/pkg/models"
+		if s := bast.PkgStruct(path, "TestStruct2"); s == nil || s.GetPackage().Path != path {
+			t.Error("Expected indexed lookup of models.TestStruct2")
+		}
+	})
+}
+
+// BenchmarkStructMethods compares indexed method lookup of every struct in
+// a large package to scanning all files of the package.
+func BenchmarkStructMethods(b *testing.B) {
+	bast := syntheticBast(2000)
+	structs := bast.AllStructs()
+
+	b.Run("Indexed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, s := range structs {
+				_ = s.Methods()
+			}
+		}
+	})
+
+	b.Run("Scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, s := range structs {
+				var out []*Method
+				for _, file := range s.GetPackage().Files.Values() {
+					for _, decl := range file.Declarations.Values() {
+						if m, ok := decl.(*Method); ok && m.Receiver.Type == s.Name {
+							out = append(out, m)
+						}
+					}
+				}
+				_ = out
+			}
+		}
+	})
+}
+
+// BenchmarkAnyStructIndexed compares indexed lookup of a struct by name to
+// scanning all files of all packages.
+func BenchmarkAnyStructIndexed(b *testing.B) {
+	bast := syntheticBast(2000)
+
+	b.Run("Indexed", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = bast.AnyStruct("Struct1999")
+		}
+	})
+
+	b.Run("Scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, pkg := range bast.packages.Values() {
+				for _, file := range pkg.Files.Values() {
+					if decl, ok := file.Declarations.Get("Struct1999"); ok {
+						_, _ = decl.(*Struct)
+					}
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"iter"
+
+	"github.com/vedranvuk/ds/maps"
+)
+
+// PackagesSeq returns an iterator over all parsed packages in load order.
+func (self *Bast) PackagesSeq() iter.Seq[*Package] { return valuesSeq(self.packages) }
+
+// Decls returns an iterator over top-level declarations of all parsed
+// packages in load order.
+func (self *Bast) Decls() iter.Seq[Declaration] {
+	return func(yield func(Declaration) bool) {
+		for pkg := range self.PackagesSeq() {
+			for decl := range pkg.ownDecls() {
+				if !yield(decl) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Vars returns an iterator over top-level variables of all parsed packages.
+func (self *Bast) Vars() iter.Seq[*Var] { return declsSeq[*Var](self.Decls()) }
+
+// Consts returns an iterator over top-level constants of all parsed packages.
+func (self *Bast) Consts() iter.Seq[*Const] { return declsSeq[*Const](self.Decls()) }
+
+// Funcs returns an iterator over top-level functions of all parsed packages.
+func (self *Bast) Funcs() iter.Seq[*Func] { return declsSeq[*Func](self.Decls()) }
+
+// Methods returns an iterator over top-level methods of all parsed packages.
+func (self *Bast) Methods() iter.Seq[*Method] { return declsSeq[*Method](self.Decls()) }
+
+// Types returns an iterator over top-level types of all parsed packages.
+func (self *Bast) Types() iter.Seq[*Type] { return declsSeq[*Type](self.Decls()) }
+
+// Structs returns an iterator over top-level structs of all parsed packages.
+func (self *Bast) Structs() iter.Seq[*Struct] { return declsSeq[*Struct](self.Decls()) }
+
+// Interfaces returns an iterator over top-level interfaces of all parsed
+// packages.
+func (self *Bast) Interfaces() iter.Seq[*Interface] { return declsSeq[*Interface](self.Decls()) }
+
+// FilesSeq returns an iterator over the package files in parse order.
+func (self *Package) FilesSeq() iter.Seq[*File] { return valuesSeq(self.Files) }
+
+// Decls returns an iterator over top-level declarations of the package in
+// parse order.
+func (self *Package) Decls() iter.Seq[Declaration] {
+	return func(yield func(Declaration) bool) {
+		for file := range self.FilesSeq() {
+			for decl := range file.Decls() {
+				if !yield(decl) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// ownDecls returns an iterator over top-level declarations of the package
+// in parse order, except those in copies of files of the loaded package
+// under test.
+func (self *Package) ownDecls() iter.Seq[Declaration] {
+	return func(yield func(Declaration) bool) {
+		for _, file := range self.ownFiles() {
+			for decl := range file.Decls() {
+				if !yield(decl) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Vars returns an iterator over top-level variables of the package.
+func (self *Package) Vars() iter.Seq[*Var] { return declsSeq[*Var](self.Decls()) }
+
+// Consts returns an iterator over top-level constants of the package.
+func (self *Package) Consts() iter.Seq[*Const] { return declsSeq[*Const](self.Decls()) }
+
+// Funcs returns an iterator over top-level functions of the package.
+func (self *Package) Funcs() iter.Seq[*Func] { return declsSeq[*Func](self.Decls()) }
+
+// Methods returns an iterator over top-level methods of the package.
+func (self *Package) Methods() iter.Seq[*Method] { return declsSeq[*Method](self.Decls()) }
+
+// Types returns an iterator over top-level types of the package.
+func (self *Package) Types() iter.Seq[*Type] { return declsSeq[*Type](self.Decls()) }
+
+// Structs returns an iterator over top-level structs of the package.
+func (self *Package) Structs() iter.Seq[*Struct] { return declsSeq[*Struct](self.Decls()) }
+
+// Interfaces returns an iterator over top-level interfaces of the package.
+func (self *Package) Interfaces() iter.Seq[*Interface] {
+	return declsSeq[*Interface](self.Decls())
+}
+
+// ImportsSeq returns an iterator over the file imports in parse order.
+func (self *File) ImportsSeq() iter.Seq[*ImportSpec] { return valuesSeq(self.Imports) }
+
+// Decls returns an iterator over top-level declarations of the file in parse
+// order.
+func (self *File) Decls() iter.Seq[Declaration] { return valuesSeq(self.Declarations) }
+
+// FieldsSeq returns an iterator over the struct field names and fields in
+// order of declaration.
+func (self *Struct) FieldsSeq() iter.Seq2[string, *Field] { return fieldsSeq(self.Fields) }
+
+// TypeParamsSeq returns an iterator over the struct type parameter names and
+// type parameters in order of declaration.
+func (self *Struct) TypeParamsSeq() iter.Seq2[string, *Field] { return fieldsSeq(self.TypeParams) }
+
+// MethodsSeq returns an iterator over the interface method names and methods
+// in order of declaration.
+func (self *Interface) MethodsSeq() iter.Seq2[string, *Method] {
+	return func(yield func(string, *Method) bool) {
+		self.Methods.EnumValues(func(m *Method) bool { return yield(m.Name, m) })
+	}
+}
+
+// ParamsSeq returns an iterator over the func param names and params in
+// order of declaration.
+func (self *Func) ParamsSeq() iter.Seq2[string, *Field] { return fieldsSeq(self.Params) }
+
+// ResultsSeq returns an iterator over the func result names and results in
+// order of declaration.
+func (self *Func) ResultsSeq() iter.Seq2[string, *Field] { return fieldsSeq(self.Results) }
+
+// TypeParamsSeq returns an iterator over the func type parameter names and
+// type parameters in order of declaration.
+func (self *Func) TypeParamsSeq() iter.Seq2[string, *Field] { return fieldsSeq(self.TypeParams) }
+
+// valuesSeq returns an iterator over values of ordered map m.
+func valuesSeq[K comparable, V any](m *maps.OrderedMap[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		if m != nil {
+			m.EnumValues(yield)
+		}
+	}
+}
+
+// fieldsSeq returns an iterator over names and fields of FieldMap m.
+func fieldsSeq(m *FieldMap) iter.Seq2[string, *Field] {
+	return func(yield func(string, *Field) bool) {
+		if m != nil {
+			m.EnumValues(func(f *Field) bool { return yield(f.Name, f) })
+		}
+	}
+}
+
+// declsSeq returns an iterator over declarations of type T from decls.
+func declsSeq[T declarations](decls iter.Seq[Declaration]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for decl := range decls {
+			if v, ok := decl.(T); ok && !yield(v) {
+				return
+			}
+		}
+	}
+}
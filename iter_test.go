@@ -0,0 +1,136 @@
+package bast
+
+import (
+	"testing"
+)
+
+// TestIterators tests iterator variants of model collection queries
+func TestIterators(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	t.Run("Bast", func(t *testing.T) {
+		var packages []*Package
+		for pkg := range bast.PackagesSeq() {
+			packages = append(packages, pkg)
+		}
+		if !equalSeq(packages, bast.AllPackages()) {
+			t.Error("Expected PackagesSeq to match AllPackages")
+		}
+		var structs []*Struct
+		for s := range bast.Structs() {
+			structs = append(structs, s)
+		}
+		if !equalSeq(structs, bast.AllStructs()) {
+			t.Error("Expected Structs to match AllStructs")
+		}
+		var funcs []*Func
+		for f := range bast.Funcs() {
+			funcs = append(funcs, f)
+		}
+		if !equalSeq(funcs, bast.AllFuncs()) {
+			t.Error("Expected Funcs to match AllFuncs")
+		}
+		var methods []*Method
+		for m := range bast.Methods() {
+			methods = append(methods, m)
+		}
+		if !equalSeq(methods, bast.AllMethods()) {
+			t.Error("Expected Methods to match AllMethods")
+		}
+	})
+
+	t.Run("Package", func(t *testing.T) {
+		pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/ctors")
+		if pkg == nil {
+			t.Fatal("Expected to find ctors package")
+		}
+		var structs []*Struct
+		for s := range pkg.Structs() {
+			structs = append(structs, s)
+		}
+		if !equalSeq(structs, bast.PkgStructs(pkg.Path)) {
+			t.Error("Expected Package.Structs to match PkgStructs")
+		}
+		var count int
+		for file := range pkg.FilesSeq() {
+			for range file.Decls() {
+				count++
+			}
+		}
+		var decls int
+		for range pkg.Decls() {
+			decls++
+		}
+		if count == 0 || count != decls {
+			t.Errorf("Expected %d declarations, got %d", count, decls)
+		}
+	})
+
+	t.Run("Fields", func(t *testing.T) {
+		s := bast.PkgStruct("github.com/vedranvuk/bast/_testproject/pkg/handlers", "User")
+		if s == nil {
+			t.Fatal("Expected to find struct User")
+		}
+		var names []string
+		for name, field := range s.FieldsSeq() {
+			if field.Name != name {
+				t.Errorf("Expected field name %s, got %s", name, field.Name)
+			}
+			names = append(names, name)
+		}
+		if !equalSeq(names, s.Fields.Keys()) {
+			t.Errorf("Expected fields %v, got %v", s.Fields.Keys(), names)
+		}
+
+		f := bast.PkgFunc("github.com/vedranvuk/bast/_testproject/pkg/handlers", "CreateUser")
+		if f == nil {
+			t.Fatal("Expected to find func CreateUser")
+		}
+		var params, results []string
+		for name := range f.ParamsSeq() {
+			params = append(params, name)
+		}
+		for _, field := range f.ResultsSeq() {
+			results = append(results, field.Type)
+		}
+		if !equalSeq(params, []string{"ctx", "user"}) || !equalSeq(results, []string{"*User", "error"}) {
+			t.Errorf("Unexpected params %v and results %v", params, results)
+		}
+
+		intf := bast.PkgInterface("github.com/vedranvuk/bast/_testproject/pkg/handlers", "Service")
+		if intf == nil {
+			t.Fatal("Expected to find interface Service")
+		}
+		for name, m := range intf.MethodsSeq() {
+			if name != "GetUser" || m.Name != name {
+				t.Errorf("Unexpected method %s", name)
+			}
+		}
+	})
+
+	t.Run("EarlyExit", func(t *testing.T) {
+		var count int
+		for range bast.Decls() {
+			count++
+			if count == 3 {
+				break
+			}
+		}
+		if count != 3 {
+			t.Errorf("Expected to stop after 3 declarations, got %d", count)
+		}
+	})
+}
+
+// equalSeq returns true if a and b contain equal elements in the same order.
+func equalSeq[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
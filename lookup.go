@@ -0,0 +1,202 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned by [Bast.Lookup] if a declaration is not found.
+	ErrNotFound = errors.New("not found")
+	// ErrAmbiguous is returned by [Bast.Lookup] if a name matches more than
+	// one package or declaration.
+	ErrAmbiguous = errors.New("ambiguous")
+)
+
+// Lookup returns the declaration at path, a package followed by a dotted
+// chain of names through declarations, struct fields, methods and interface
+// methods, e.g. "github.com/org/repo/pkg.Type.Field" or "pkg.Type.Method".
+// Methods are only reached through their receiver type.
+//
+// The package is given by its import path or, if path contains no slash,
+// by its import path or name. If the first name of a path without a slash
+// is not a package, the chain is resolved starting from a declaration of
+// that name in any package, e.g. "Type.Field".
+//
+// A name following a struct field resolves through the field's type if it
+// is a possibly pointer or instantiated named type of a loaded package.
+//
+// Result is a *Var, *Const, *Func, *Method, *Type, *Struct, *Interface or
+// *Field. Test variant packages are not searched unless given by ID.
+//
+// It returns an error wrapping [ErrAmbiguous] if a package name or a
+// declaration name without a package matches more than one package and
+// [ErrNotFound] if any name in path is not found.
+func (self *Bast) Lookup(path string) (Declaration, error) {
+
+	var pkg, chain, err = self.lookupPackage(path)
+	if err != nil {
+		return nil, fmt.Errorf("lookup %s: %w", path, err)
+	}
+
+	var decl Declaration
+	if pkg != nil {
+		var ok bool
+		if decl, ok = pkg.index().names[chain[0]]; !ok {
+			return nil, fmt.Errorf("lookup %s: %s.%s %w", path, pkg.Path, chain[0], ErrNotFound)
+		}
+	} else {
+		var decls []Declaration
+		for _, d := range self.nameIndex()[chain[0]] {
+			if p := d.GetPackage(); p.ID == p.Path {
+				decls = append(decls, d)
+			}
+		}
+		switch len(decls) {
+		case 0:
+			return nil, fmt.Errorf("lookup %s: %s %w", path, chain[0], ErrNotFound)
+		case 1:
+			decl = decls[0]
+		default:
+			var paths []string
+			for _, d := range decls {
+				paths = append(paths, d.GetPackage().Path)
+			}
+			return nil, fmt.Errorf("lookup %s: %s is %w, declared in %s",
+				path, chain[0], ErrAmbiguous, strings.Join(paths, ", "))
+		}
+	}
+
+	for i, name := range chain[1:] {
+		var member = self.member(decl, name)
+		if member == nil {
+			return nil, fmt.Errorf("lookup %s: %s has no member %s: %w",
+				path, strings.Join(chain[:i+1], "."), name, ErrNotFound)
+		}
+		decl = member
+	}
+
+	return decl, nil
+}
+
+// lookupPackage returns the package named by path and the chain of names
+// following it. Package is nil if path does not start with a package.
+func (self *Bast) lookupPackage(path string) (*Package, []string, error) {
+
+	if i := strings.LastIndexByte(path, '/'); i >= 0 {
+		var names = strings.Split(path[i+1:], ".")
+		for k := len(names) - 1; k > 0; k-- {
+			if pkg := self.PackageByPath(path[:i+1] + strings.Join(names[:k], ".")); pkg != nil {
+				return pkg, names[k:], nil
+			}
+		}
+		return nil, nil, fmt.Errorf("package %w", ErrNotFound)
+	}
+
+	var names = strings.Split(path, ".")
+	for _, name := range names {
+		if name == "" {
+			return nil, nil, errors.New("invalid path")
+		}
+	}
+	if len(names) == 1 {
+		return nil, names, nil
+	}
+	if pkg := self.PackageByPath(names[0]); pkg != nil {
+		return pkg, names[1:], nil
+	}
+
+	var found []*Package
+	for _, pkg := range self.packages.Values() {
+		if pkg.ID == pkg.Path && pkg.Name == names[0] {
+			found = append(found, pkg)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, names, nil
+	case 1:
+		return found[0], names[1:], nil
+	}
+	var paths []string
+	for _, pkg := range found {
+		paths = append(paths, pkg.Path)
+	}
+	return nil, nil, fmt.Errorf("package %s is %w: %s", names[0], ErrAmbiguous, strings.Join(paths, ", "))
+}
+
+// member returns the field, method or interface method named name of decl
+// or nil if not found.
+func (self *Bast) member(decl Declaration, name string) Declaration {
+
+	switch d := decl.(type) {
+	case *Struct:
+		if field, ok := d.Fields.Get(name); ok {
+			return field
+		}
+		return methodOf(d.GetPackage(), d.Name, name)
+	case *Type:
+		return methodOf(d.GetPackage(), d.Name, name)
+	case *Interface:
+		if m, ok := d.Methods.Get(name); ok {
+			return m
+		}
+	case *Field:
+		if typ := self.fieldTypeDecl(d); typ != nil {
+			return self.member(typ, name)
+		}
+	}
+
+	return nil
+}
+
+// fieldTypeDecl returns the declaration of the named type of field or nil
+// if the field type is not a possibly pointer or instantiated named type of
+// a loaded package.
+func (self *Bast) fieldTypeDecl(field *Field) Declaration {
+
+	if field.file == nil {
+		return nil
+	}
+	var typ = strings.TrimPrefix(field.Type, "*")
+	if i := strings.IndexByte(typ, '['); i > 0 {
+		typ = typ[:i]
+	}
+
+	var pkg = field.GetPackage()
+	if _, name, ok := strings.Cut(typ, "."); ok {
+		var imp = field.ImportSpecBySelectorExpr(typ)
+		if imp == nil {
+			return nil
+		}
+		if pkg, ok = self.packages.Get(imp.Path); !ok {
+			return nil
+		}
+		typ = name
+	}
+	if strings.ContainsAny(typ, "*[]() ") {
+		return nil
+	}
+
+	var decl, ok = pkg.index().names[typ]
+	if !ok {
+		return nil
+	}
+	return decl
+}
+
+// methodOf returns the method named name of type typeName declared in pkg
+// or nil if not found.
+func methodOf(pkg *Package, typeName, name string) Declaration {
+	for _, m := range pkg.index().receivers[typeName] {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package bast
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestLookup tests lookup of declarations by dotted path
+func TestLookup(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	deps := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/deps")
+	if deps == nil {
+		t.Fatal("Expected to find deps package")
+	}
+	customer := deps.Struct("Customer")
+	address := deps.Struct("Address")
+	country := deps.Struct("Country")
+
+	t.Run("Resolve", func(t *testing.T) {
+		tests := []struct {
+			path     string
+			expected Declaration
+		}{
+			{"github.com/vedranvuk/bast/_testproject/pkg/deps.Customer", customer},
+			{"github.com/vedranvuk/bast/_testproject/pkg/deps.Customer.Name", mapValue(customer.Fields, "Name")},
+			{"deps.Customer.Valid", deps.Method("Valid")},
+			{"deps.Repository.Find", mapValue(deps.Interface("Repository").Methods, "Find")},
+			{"deps.Address.Country.Code", mapValue(country.Fields, "Code")},
+			{"deps.DefaultCountry", deps.Var("DefaultCountry")},
+			{"Address.Street", mapValue(address.Fields, "Street")},
+			{"handlers.Server.GetUser", bast.PkgMethod("github.com/vedranvuk/bast/_testproject/pkg/handlers", "GetUser")},
+		}
+		for _, test := range tests {
+			decl, err := bast.Lookup(test.path)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.path, err)
+				continue
+			}
+			if decl != test.expected {
+				t.Errorf("%s: unexpected declaration %#v", test.path, decl)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			path     string
+			expected error
+		}{
+			{"User", ErrAmbiguous},
+			{"User.Name", ErrAmbiguous},
+			{"Missing", ErrNotFound},
+			{"deps.Customer.Missing", ErrNotFound},
+			{"deps.Valid", ErrNotFound},
+			{"Valid", ErrNotFound},
+			{"handlers.GetUser", ErrNotFound},
+			{"deps.Customer.Tags.Key", ErrNotFound},
+			{"github.com/vedranvuk/bast/_testproject/pkg/missing.Type", ErrNotFound},
+		}
+		for _, test := range tests {
+			if _, err := bast.Lookup(test.path); !errors.Is(err, test.expected) {
+				t.Errorf("%s: expected %v, got %v", test.path, test.expected, err)
+			}
+		}
+		if _, err := bast.Lookup("deps..Customer"); err == nil {
+			t.Error("Expected error for invalid path")
+		}
+	})
+
+	t.Run("Qualified", func(t *testing.T) {
+		decl, err := bast.Lookup("ctors.User")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if s, ok := decl.(*Struct); !ok || s.GetPackage().Name != "ctors" {
+			t.Errorf("Expected ctors.User struct, got %#v", decl)
+		}
+	})
+}
+
+// mapValue returns the value of key from ordered map m or a zero value.
+func mapValue[V any](m interface{ Get(string) (V, bool) }, key string) V {
+	v, _ := m.Get(key)
+	return v
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"go/token"
+	"strings"
+)
+
+// DefaultNoteMarkers are the note markers always recognized in comments.
+var DefaultNoteMarkers = []string{"TODO", "FIXME", "BUG"}
+
+// Note is a marked note parsed from a comment, e.g. "TODO(owner): text".
+type Note struct {
+	// Marker is the note marker, e.g. "TODO".
+	Marker string
+	// Owner is the optional note owner specified in parentheses after the
+	// marker, e.g. "owner" in "TODO(owner): text".
+	Owner string
+	// Text is the note text. Continuation lines of the comment are joined
+	// with a single space.
+	Text string
+	// Pos is the position of the note in source.
+	Pos token.Position
+	// Decl is the top-level declaration enclosing the note, including its
+	// doc comment, or nil if the note is not inside a declaration.
+	//
+	// Methods are matched by receiver type and name. Decl is nil if the
+	// enclosing method is not in the model because a func or method of the
+	// same name declared later in the file replaced it.
+	Decl Declaration
+}
+
+// Notes returns the notes parsed from all comments in this file.
+func (self *File) Notes() []*Note { return self.notes }
+
+// Notes returns the notes parsed from all comments in all files of this
+// package.
+func (self *Package) Notes() (out []*Note) {
+	for _, file := range self.Files.Values() {
+		out = append(out, file.notes...)
+	}
+	return
+}
+
+// Deprecated returns the text of the "Deprecated:" paragraph of the
+// declaration doc comment, or an empty string if there is none.
+//
+// The paragraph must begin with "Deprecated:", at the start of the doc
+// comment or after a blank line. Paragraph lines are joined with a single
+// space.
+func (self *Model) Deprecated() string {
+	var (
+		lines = commentText(self.Doc)
+		start = true
+		out   []string
+	)
+	for _, line := range lines {
+		if out == nil {
+			if text, ok := strings.CutPrefix(line, "Deprecated:"); ok && start {
+				out = append(out, strings.TrimSpace(text))
+			}
+			start = line == ""
+			continue
+		}
+		if line == "" {
+			break
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, " ")
+}
+
+// commentText returns comment lines with comment markers and surrounding
+// whitespace removed.
+func commentText(comments []string) (out []string) {
+	for _, comment := range comments {
+		if text, ok := strings.CutPrefix(comment, "//"); ok {
+			out = append(out, strings.TrimSpace(text))
+			continue
+		}
+		comment = strings.TrimPrefix(comment, "/*")
+		comment = strings.TrimSuffix(comment, "*/")
+		for _, line := range strings.Split(comment, "\n") {
+			out = append(out, strings.TrimSpace(line))
+		}
+	}
+	return
+}
+
+// parseNote parses a note from comment text line if it starts with one of
+// markers followed by an optional owner in parentheses and an optional colon.
+// It returns nil if line is not a note.
+func parseNote(line string, markers []string) *Note {
+	for _, marker := range markers {
+		var rest, ok = strings.CutPrefix(line, marker)
+		if !ok {
+			continue
+		}
+		var note = &Note{Marker: marker}
+		if strings.HasPrefix(rest, "(") {
+			var owner, after, closed = strings.Cut(rest[1:], ")")
+			if !closed {
+				continue
+			}
+			note.Owner, rest = owner, after
+		}
+		if text, ok := strings.CutPrefix(rest, ":"); ok {
+			rest = text
+		} else if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue
+		}
+		note.Text = strings.TrimSpace(rest)
+		return note
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package bast
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestNotes tests parsing of comment notes
+func TestNotes(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Dir = "_testproject"
+	cfg.NoteMarkers = []string{"HACK"}
+	bast, err := Load(cfg, "./pkg/models")
+	if err != nil {
+		t.Fatalf("Failed to load test project: %v", err)
+	}
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/models")
+	if pkg == nil {
+		t.Fatal("Expected to find models package")
+	}
+
+	notes := pkg.Notes()
+	if len(notes) != 4 {
+		for _, note := range notes {
+			t.Logf("%s(%s): %s", note.Marker, note.Owner, note.Text)
+		}
+		t.Fatalf("Expected 4 notes, got %d", len(notes))
+	}
+
+	tests := []struct {
+		marker string
+		owner  string
+		text   string
+		line   int
+		decl   Declaration
+	}{
+		{"TODO", "vedran", "Move notes into their own package.", 3, nil},
+		{"FIXME", "", "this does nothing and spans two lines.", 12, pkg.Func("DeprecatedFunc")},
+		{"BUG", "vedran", "Other has no purpose.", 22, pkg.Struct("NotedStruct")},
+		{"HACK", "", "a custom note marker in a block comment.", 27, pkg.Var("NotedVar")},
+	}
+	for i, test := range tests {
+		note := notes[i]
+		if note.Marker != test.marker || note.Owner != test.owner || note.Text != test.text {
+			t.Errorf("Expected note %s(%s): %q, got %s(%s): %q",
+				test.marker, test.owner, test.text, note.Marker, note.Owner, note.Text)
+		}
+		if filepath.Base(note.Pos.Filename) != "notes.go" || note.Pos.Line != test.line {
+			t.Errorf("Expected note %s at notes.go:%d, got %s", test.marker, test.line, note.Pos)
+		}
+		if note.Decl != test.decl {
+			t.Errorf("Unexpected enclosing declaration for note %s: %v", test.marker, note.Decl)
+		}
+	}
+
+	if file := pkg.Files.Values()[0]; len(file.Notes()) != 0 {
+		t.Errorf("Expected no notes in %s, got %d", file.Name, len(file.Notes()))
+	}
+}
+
+// TestNoteMethodDecl tests resolving notes inside methods of different
+// receivers sharing a name
+func TestNoteMethodDecl(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/notetest")
+	if pkg == nil {
+		t.Fatal("Expected to find notetest package")
+	}
+	notes := pkg.Notes()
+	if len(notes) != 2 {
+		t.Fatalf("Expected 2 notes, got %d", len(notes))
+	}
+
+	// A.String is replaced by B.String in the model as they share a name.
+	if notes[0].Decl != nil {
+		t.Errorf("Expected no declaration for note inside A.String, got %v", notes[0].Decl)
+	}
+	if m, ok := notes[1].Decl.(*Method); !ok || m.Name != "String" || m.Receiver.Type != "B" {
+		t.Errorf("Expected note inside B.String to be enclosed by B.String, got %v", notes[1].Decl)
+	}
+}
+
+// TestDeprecated tests extraction of deprecation notices
+func TestDeprecated(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/models")
+	if pkg == nil {
+		t.Fatal("Expected to find models package")
+	}
+
+	if deprecated := pkg.Func("DeprecatedFunc").Deprecated(); deprecated != "Use TestFunc1 instead. It will be removed." {
+		t.Errorf("Unexpected deprecation notice: %q", deprecated)
+	}
+	field, _ := pkg.Struct("NotedStruct").Fields.Get("Field")
+	if deprecated := field.Deprecated(); deprecated != "Do not use." {
+		t.Errorf("Unexpected field deprecation notice: %q", deprecated)
+	}
+	if deprecated := pkg.Func("TestFunc1").Deprecated(); deprecated != "" {
+		t.Errorf("Expected no deprecation notice, got %q", deprecated)
+	}
+}
+
+// TestDeprecatedParagraph tests that deprecation notices must start a
+// paragraph
+func TestDeprecatedParagraph(t *testing.T) {
+	tests := []struct {
+		doc      []string
+		expected string
+	}{
+		{[]string{"// Deprecated: Use G."}, "Use G."},
+		{[]string{"// F is old.", "//", "// Deprecated: Use G."}, "Use G."},
+		{[]string{"/*\nF is old.\n\nDeprecated: Use G.\n*/"}, "Use G."},
+		{[]string{"// F reports whether a comment has a", "// Deprecated: notice."}, ""},
+		{[]string{"// F is old. Deprecated: Use G."}, ""},
+	}
+	for _, test := range tests {
+		fn := NewFunc(nil, "F")
+		fn.Doc = test.doc
+		if deprecated := fn.Deprecated(); deprecated != test.expected {
+			t.Errorf("Deprecated() of %q = %q, expected %q", test.doc, deprecated, test.expected)
+		}
+	}
+}
+
+// TestParseNote tests parsing of a single note line
+func TestParseNote(t *testing.T) {
+	markers := DefaultNoteMarkers
+	tests := []struct {
+		line   string
+		marker string
+		owner  string
+		text   string
+	}{
+		{"TODO(owner): text", "TODO", "owner", "text"},
+		{"TODO: text", "TODO", "", "text"},
+		{"FIXME text", "FIXME", "", "text"},
+		{"BUG(who)", "BUG", "who", ""},
+		{"TODOS are not notes", "", "", ""},
+		{"BUG(who is not closed", "", "", ""},
+		{"Not a TODO", "", "", ""},
+	}
+	for _, test := range tests {
+		note := parseNote(test.line, markers)
+		if test.marker == "" {
+			if note != nil {
+				t.Errorf("Expected no note for %q, got %s", test.line, note.Marker)
+			}
+			continue
+		}
+		if note == nil {
+			t.Errorf("Expected note for %q", test.line)
+			continue
+		}
+		if note.Marker != test.marker || note.Owner != test.owner || note.Text != test.text {
+			t.Errorf("Unexpected note for %q: %s(%s): %q", test.line, note.Marker, note.Owner, note.Text)
+		}
+	}
+}
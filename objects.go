@@ -0,0 +1,264 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"go/types"
+)
+
+// objectOf returns the type checker object declared by decl or nil if not
+// found or if type checking was not enabled.
+//
+// Supported are top-level declarations, methods, interface methods and
+// fields of top-level structs.
+func objectOf(decl Declaration) types.Object {
+
+	var pkg = decl.GetPackage()
+	if pkg == nil || pkg.pkg == nil || pkg.pkg.Types == nil {
+		return nil
+	}
+	var scope = pkg.pkg.Types.Scope()
+
+	switch d := decl.(type) {
+	case *Var:
+		return scope.Lookup(d.Name)
+	case *Const:
+		return scope.Lookup(d.Name)
+	case *Func:
+		return scope.Lookup(d.Name)
+	case *Type:
+		return scope.Lookup(d.Name)
+	case *Struct:
+		return scope.Lookup(d.Name)
+	case *Interface:
+		return scope.Lookup(d.Name)
+	case *Method:
+		if d.Receiver != nil {
+			var named = namedOf(scope.Lookup(d.Receiver.Type))
+			if named == nil {
+				return nil
+			}
+			for i := 0; i < named.NumMethods(); i++ {
+				if m := named.Method(i); m.Name() == d.Name {
+					return m
+				}
+			}
+			return nil
+		}
+		var intf = interfaceOf(d)
+		if intf == nil {
+			return nil
+		}
+		var named = namedOf(scope.Lookup(intf.Name))
+		if named == nil {
+			return nil
+		}
+		if it, ok := named.Underlying().(*types.Interface); ok {
+			for i := 0; i < it.NumExplicitMethods(); i++ {
+				if m := it.ExplicitMethod(i); m.Name() == d.Name {
+					return m
+				}
+			}
+		}
+		return nil
+	case *Field:
+		for _, s := range pkgDecls[*Struct](pkg.ID, pkg.bast.packages) {
+			var idx = -1
+			for i, f := range s.Fields.Values() {
+				if f == d {
+					idx = i
+					break
+				}
+			}
+			if idx < 0 {
+				continue
+			}
+			var named = namedOf(scope.Lookup(s.Name))
+			if named == nil {
+				return nil
+			}
+			if st, ok := named.Underlying().(*types.Struct); ok && idx < st.NumFields() {
+				return st.Field(idx)
+			}
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// declOf returns the bast declaration of type checker object obj or nil if
+// not found.
+//
+// It is the inverse of objectOf.
+func (self *Bast) declOf(obj types.Object) Declaration {
+
+	if obj == nil || obj.Pkg() == nil {
+		return nil
+	}
+	obj = originOf(obj)
+
+	var pkg *Package
+	for _, p := range self.packages.Values() {
+		if p.pkg != nil && p.pkg.Types == obj.Pkg() {
+			pkg = p
+			break
+		}
+	}
+	if pkg == nil {
+		return nil
+	}
+	var scope = pkg.pkg.Types.Scope()
+
+	switch o := obj.(type) {
+	case *types.Func:
+		var recv = o.Signature().Recv()
+		if recv == nil {
+			break
+		}
+		var named = namedOf(recv)
+		if named == nil {
+			return nil
+		}
+		if _, isIntf := named.Underlying().(*types.Interface); isIntf {
+			var intf = pkgDecl[*Interface](pkg.ID, named.Obj().Name(), self.packages)
+			if intf == nil {
+				return nil
+			}
+			if m, ok := intf.Methods.Get(o.Name()); ok {
+				return m
+			}
+			return nil
+		}
+		for _, m := range pkgDecls[*Method](pkg.ID, self.packages) {
+			if m.Receiver.Type == named.Obj().Name() && m.Name == o.Name() {
+				return m
+			}
+		}
+		return nil
+	case *types.Var:
+		if !o.IsField() {
+			break
+		}
+		for _, s := range pkgDecls[*Struct](pkg.ID, self.packages) {
+			var named = namedOf(scope.Lookup(s.Name))
+			if named == nil {
+				continue
+			}
+			var st, ok = named.Underlying().(*types.Struct)
+			if !ok {
+				continue
+			}
+			for i := 0; i < st.NumFields() && i < s.Fields.Len(); i++ {
+				if st.Field(i) == o {
+					return s.Fields.Values()[i]
+				}
+			}
+		}
+		return nil
+	}
+
+	// Init funcs are not declared in package scope.
+	if scope.Lookup(obj.Name()) != obj && obj.Name() != "init" {
+		return nil
+	}
+	for _, file := range pkg.Files.Values() {
+		if decl, ok := file.Declarations.Get(obj.Name()); ok {
+			if _, isMethod := decl.(*Method); !isMethod {
+				return decl
+			}
+		}
+	}
+
+	return nil
+}
+
+// originOf returns the generic origin of obj if obj is an instantiated
+// function, method or field, otherwise returns obj.
+func originOf(obj types.Object) types.Object {
+	switch o := obj.(type) {
+	case nil:
+		return nil
+	case *types.Func:
+		return o.Origin()
+	case *types.Var:
+		return o.Origin()
+	}
+	return obj
+}
+
+// namedOf returns the named type of object or type v, dereferencing
+// pointers, or nil if v is not of a named type.
+func namedOf(v any) *types.Named {
+	var t types.Type
+	switch o := v.(type) {
+	case types.Object:
+		t = o.Type()
+	case types.Type:
+		t = o
+	default:
+		return nil
+	}
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Origin()
+	}
+	return nil
+}
+
+// qualifiedName returns the name of decl qualified by its package ID, e.g.
+// "example.com/pkg.Func", "example.com/pkg.(*Type).Method" or
+// "example.com/pkg.Interface.Method".
+func qualifiedName(decl Declaration) string {
+	var prefix string
+	if pkg := decl.GetPackage(); pkg != nil {
+		prefix = pkg.ID + "."
+	}
+	switch d := decl.(type) {
+	case *Var:
+		return prefix + d.Name
+	case *Const:
+		return prefix + d.Name
+	case *Func:
+		return prefix + d.Name
+	case *Type:
+		return prefix + d.Name
+	case *Struct:
+		return prefix + d.Name
+	case *Interface:
+		return prefix + d.Name
+	case *Method:
+		if d.Receiver == nil {
+			if intf := interfaceOf(d); intf != nil {
+				return prefix + intf.Name + "." + d.Name
+			}
+			return prefix + d.Name
+		}
+		if d.Receiver.Pointer {
+			return prefix + "(*" + d.Receiver.Type + ")." + d.Name
+		}
+		return prefix + d.Receiver.Type + "." + d.Name
+	case *Field:
+		return prefix + d.Name
+	}
+	return ""
+}
+
+// interfaceOf returns the interface declaring interface method m or nil if
+// not found.
+func interfaceOf(m *Method) *Interface {
+	var pkg = m.GetPackage()
+	if pkg == nil {
+		return nil
+	}
+	for _, intf := range pkgDecls[*Interface](pkg.ID, pkg.bast.packages) {
+		if v, ok := intf.Methods.Get(m.Name); ok && v == m {
+			return intf
+		}
+	}
+	return nil
+}
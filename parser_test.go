@@ -740,4 +740,62 @@ func TestParserErrorCases(t *testing.T) {
 			t.Errorf("Expected empty string for type resolution without type checking, got '%s'", resolved)
 		}
 	})
-}
\ No newline at end of file
+}
+
+// TestGeneratedFiles tests detection and filtering of generated files
+func TestGeneratedFiles(t *testing.T) {
+	const path = "github.com/vedranvuk/bast/_testproject/pkg/models"
+
+	t.Run("Detection", func(t *testing.T) {
+		bast := loadTestProject(t, false)
+		pkg := bast.PackageByPath(path)
+		if pkg == nil {
+			t.Fatal("Expected to find models package")
+		}
+		var generated []*File
+		for _, file := range pkg.Files.Values() {
+			if file.IsGenerated {
+				generated = append(generated, file)
+			} else if file.Generator != "" {
+				t.Errorf("Expected no generator for %s, got '%s'", file.Name, file.Generator)
+			}
+		}
+		if len(generated) != 1 {
+			t.Fatalf("Expected 1 generated file, got %d", len(generated))
+		}
+		if !strings.HasSuffix(generated[0].Name, "zz_generated.go") {
+			t.Errorf("Expected zz_generated.go to be generated, got %s", generated[0].Name)
+		}
+		if generated[0].Generator != "bastgen -type=GeneratedStruct" {
+			t.Errorf("Unexpected generator '%s'", generated[0].Generator)
+		}
+		if pkg.Struct("GeneratedStruct") == nil {
+			t.Error("Expected GeneratedStruct to be parsed")
+		}
+	})
+
+	t.Run("SkipGenerated", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Dir = "_testproject"
+		cfg.SkipGenerated = true
+		bast, err := Load(cfg, "./pkg/models")
+		if err != nil {
+			t.Fatalf("Failed to load: %v", err)
+		}
+		pkg := bast.PackageByPath(path)
+		if pkg == nil {
+			t.Fatal("Expected to find models package")
+		}
+		for _, file := range pkg.Files.Values() {
+			if file.IsGenerated {
+				t.Errorf("Expected generated file %s to be skipped", file.Name)
+			}
+		}
+		if pkg.Struct("GeneratedStruct") != nil {
+			t.Error("Expected GeneratedStruct to be skipped")
+		}
+		if pkg.Struct("TestStruct1") == nil {
+			t.Error("Expected TestStruct1 to be parsed")
+		}
+	})
+}
@@ -0,0 +1,286 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// ImportContext provides package qualifiers of the file code is emitted to.
+type ImportContext interface {
+	// Qualifier returns the name qualifying identifiers of the package with
+	// import path pkgPath or an empty string if identifiers of the package
+	// are not qualified, i.e. the package is the package of the file or is
+	// dot-imported.
+	Qualifier(pkgPath string) string
+}
+
+// ImportContextFunc is a func implementing [ImportContext].
+type ImportContextFunc func(pkgPath string) string
+
+// Qualifier implements ImportContext.
+func (self ImportContextFunc) Qualifier(pkgPath string) string { return self(pkgPath) }
+
+// Qualifier implements [ImportContext] for code emitted to the file.
+//
+// It returns the name the file imports pkgPath as or the assumed package
+// name if pkgPath is not imported by the file.
+func (self *File) Qualifier(pkgPath string) string {
+	if self.pkg != nil && self.pkg.Path == pkgPath {
+		return ""
+	}
+	if imp, ok := self.Imports.Get(pkgPath); ok {
+		if imp.Name == "." {
+			return ""
+		}
+		return self.importName(imp)
+	}
+	return importBase(pkgPath)
+}
+
+// QualifyType rewrites type expression expr declared in file from so it is
+// valid in code emitted to a file with import context to.
+//
+// Names declared in the package of from are qualified with the package
+// qualifier in to. Names qualified by an import of from, including aliased
+// imports, and names of dot-imported packages are qualified with the
+// qualifier of their package in to. Predeclared names and names not
+// declared in the package of from, such as type parameters, are not
+// changed. Type parameters shadowing a package level name are qualified,
+// use [Bast.QualifyDeclType] to qualify types used in a generic declaration.
+//
+// Dot-imported names are resolved only if Config.TypeChecking is enabled or
+// the dot-imported package is loaded.
+//
+// It returns an error if expr is not a valid type expression or if it
+// references a package not imported by from.
+func (self *Bast) QualifyType(expr string, from *File, to ImportContext) (string, error) {
+	return self.qualifyType(expr, from, nil, to)
+}
+
+// QualifyDeclType is like [Bast.QualifyType] for type expression expr used
+// in declaration decl, e.g. the type of one of its fields or parameters.
+//
+// Names of type parameters of decl and of the receiver of a method are not
+// qualified, even if they shadow a package level or imported name.
+// Receiver type parameter names are known only if Config.TypeChecking is
+// enabled, otherwise type parameters of the receiver type are used.
+func (self *Bast) QualifyDeclType(expr string, decl Declaration, to ImportContext) (string, error) {
+	return self.qualifyType(expr, decl.GetFile(), typeParamNames(decl), to)
+}
+
+// qualifyType implements QualifyType, leaving names in bound unchanged.
+func (self *Bast) qualifyType(expr string, from *File, bound map[string]bool, to ImportContext) (string, error) {
+
+	var variadic bool
+	if elt, ok := strings.CutPrefix(expr, "..."); ok {
+		expr, variadic = elt, true
+	}
+	var node, err = parser.ParseExpr(expr)
+	if err != nil {
+		return "", fmt.Errorf("invalid type expression %q: %w", expr, err)
+	}
+
+	var qualified = func(pkgPath, name string) ast.Expr {
+		if q := to.Qualifier(pkgPath); q != "" {
+			return &ast.SelectorExpr{X: ast.NewIdent(q), Sel: ast.NewIdent(name)}
+		}
+		return ast.NewIdent(name)
+	}
+	if node, err = self.qualifyNode(node, from, bound, qualified); err != nil {
+		return "", err
+	}
+
+	var out = types.ExprString(node)
+	if variadic {
+		out = "..." + out
+	}
+	return out, nil
+}
+
+// qualifyNode rewrites type expression node declared in file from, using
+// qualified to qualify names not in bound.
+func (self *Bast) qualifyNode(node ast.Expr, from *File, bound map[string]bool, qualified func(pkgPath, name string) ast.Expr) (ast.Expr, error) {
+
+	var err error
+	var out = astutil.Apply(node, func(c *astutil.Cursor) bool {
+		if err != nil {
+			return false
+		}
+		switch n := c.Node().(type) {
+		case *ast.Field:
+			// Rewrite field types only, not field or method names.
+			if n.Type != nil {
+				n.Type, err = self.qualifyNode(n.Type, from, bound, qualified)
+			}
+			return false
+		case *ast.SelectorExpr:
+			var x, ok = n.X.(*ast.Ident)
+			if !ok || bound[x.Name] {
+				return false
+			}
+			var imp = from.importByName(x.Name)
+			if imp == nil {
+				err = fmt.Errorf("package %s is not imported by %s", x.Name, from.Name)
+				return false
+			}
+			c.Replace(qualified(imp.Path, n.Sel.Name))
+			return false
+		case *ast.Ident:
+			if !bound[n.Name] {
+				c.Replace(self.qualifyIdent(n, from, qualified))
+			}
+			return false
+		}
+		return true
+	}, nil)
+
+	return out.(ast.Expr), err
+}
+
+// qualifyIdent returns ident qualified using qualified if it names a
+// declaration of the package of from or of a package dot-imported by from.
+func (self *Bast) qualifyIdent(ident *ast.Ident, from *File, qualified func(pkgPath, name string) ast.Expr) ast.Expr {
+
+	if obj := types.Universe.Lookup(ident.Name); obj != nil {
+		return ident
+	}
+	var pkg = from.pkg
+	if pkg == nil {
+		return ident
+	}
+	if _, ok := pkg.index().names[ident.Name]; ok {
+		return qualified(pkg.Path, ident.Name)
+	}
+
+	for _, imp := range from.Imports.Values() {
+		if imp.Name != "." {
+			continue
+		}
+		if p, ok := self.packages.Get(imp.Path); ok {
+			if _, ok := p.index().names[ident.Name]; ok {
+				return qualified(imp.Path, ident.Name)
+			}
+		}
+		if pkg.pkg != nil && pkg.pkg.Imports[imp.Path] != nil && pkg.pkg.Imports[imp.Path].Types != nil {
+			if pkg.pkg.Imports[imp.Path].Types.Scope().Lookup(ident.Name) != nil {
+				return qualified(imp.Path, ident.Name)
+			}
+		}
+	}
+
+	return ident
+}
+
+// typeParamNames returns the set of type parameter names of decl and of
+// the receiver of decl if it is a method.
+func typeParamNames(decl Declaration) map[string]bool {
+
+	var (
+		out = make(map[string]bool)
+		add = func(params *FieldMap) {
+			for _, name := range params.Keys() {
+				out[name] = true
+			}
+		}
+	)
+	switch d := decl.(type) {
+	case *Func:
+		add(d.TypeParams)
+	case *Method:
+		add(d.TypeParams)
+		if d.Receiver == nil {
+			break
+		}
+		if fn, ok := objectOf(d).(*types.Func); ok {
+			var list = fn.Signature().RecvTypeParams()
+			for i := 0; i < list.Len(); i++ {
+				out[list.At(i).Obj().Name()] = true
+			}
+			break
+		}
+		if pkg := d.GetPackage(); pkg != nil {
+			if recv, ok := pkg.index().names[strings.TrimLeft(d.Receiver.Type, "*")]; ok {
+				for name := range typeParamNames(recv) {
+					out[name] = true
+				}
+			}
+		}
+	case *Type:
+		add(d.TypeParams)
+	case *Struct:
+		add(d.TypeParams)
+	case *Interface:
+		add(d.TypeParams)
+	}
+
+	return out
+}
+
+// importByName returns the import of the file whose identifiers are
+// qualified by name or nil if not found.
+func (self *File) importByName(name string) *ImportSpec {
+	for _, imp := range self.Imports.Values() {
+		if imp.Name != "." && imp.Name != "_" && self.importName(imp) == name {
+			return imp
+		}
+	}
+	return nil
+}
+
+// importName returns the name identifiers of the package imported by imp
+// are qualified with in the file.
+//
+// It is the import alias if imp is named, otherwise the package name if
+// Config.TypeChecking is enabled or the package is loaded, otherwise the
+// assumed package name from the import path, see [importBase].
+func (self *File) importName(imp *ImportSpec) string {
+	if imp.Name != "" {
+		return imp.Name
+	}
+	if self.pkg != nil && self.pkg.pkg != nil {
+		if p := self.pkg.pkg.Imports[imp.Path]; p != nil && p.Name != "" {
+			return p.Name
+		}
+	}
+	if self.pkg != nil && self.pkg.bast != nil {
+		if p, ok := self.pkg.bast.packages.Get(imp.Path); ok {
+			return p.Name
+		}
+	}
+	return importBase(imp.Path)
+}
+
+// importBase returns the assumed package name of import path pkgPath, its
+// last element without a major version suffix, i.e. "yaml" for
+// "gopkg.in/yaml.v3" and "chi" for "github.com/go-chi/chi/v5".
+func importBase(pkgPath string) string {
+	var base = path.Base(pkgPath)
+	if isMajorVersion(base) && path.Dir(pkgPath) != "." {
+		base = path.Base(path.Dir(pkgPath))
+	}
+	if i := strings.LastIndex(base, ".v"); i > 0 && isMajorVersion(base[i+1:]) {
+		base = base[:i]
+	}
+	return base
+}
+
+// isMajorVersion returns true if s is a major version path element such as
+// "v2".
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	var _, err = strconv.Atoi(s[1:])
+	return err == nil
+}
@@ -0,0 +1,148 @@
+package bast
+
+import (
+	"testing"
+)
+
+// TestQualifyType tests rewriting of type expressions for another package
+func TestQualifyType(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	const base = "github.com/vedranvuk/bast/_testproject/pkg/"
+	edgecases := bast.PackageByPath(base + "edgecases").Files.Values()[0]
+	generics := bast.PkgFunc(base+"generics", "ProcessWithContext").GetFile()
+	models := bast.PkgStruct(base+"models", "TestStruct1").GetFile()
+
+	to := ImportContextFunc(func(pkgPath string) string {
+		switch pkgPath {
+		case base + "edgecases":
+			return "ec"
+		case base + "generics":
+			return "g"
+		case base + "types":
+			return "t"
+		case "strings":
+			return "str"
+		}
+		return importBase(pkgPath)
+	})
+
+	tests := []struct {
+		expr     string
+		from     *File
+		to       ImportContext
+		expected string
+	}{
+		{"int", edgecases, to, "int"},
+		{"types.ID", edgecases, to, "t.ID"},
+		{"aliased.Builder", edgecases, to, "str.Builder"},
+		{"Stringer", edgecases, to, "fmt.Stringer"},
+		{"RecursiveType", edgecases, to, "ec.RecursiveType"},
+		{"[]*RecursiveType", edgecases, to, "[]*ec.RecursiveType"},
+		{"map[types.ID][2]RecursiveType", edgecases, to, "map[t.ID][2]ec.RecursiveType"},
+		{"...types.ID", edgecases, to, "...t.ID"},
+		{"func(ctx context.Context, id types.ID) error", edgecases, to, "func(ctx context.Context, id t.ID) error"},
+		{"struct{ ID types.ID }", edgecases, to, "struct{ID t.ID}"},
+		{"Container[T, U]", generics, to, "g.Container[T, U]"},
+		{"func(Pair[T, U]) V", generics, to, "func(g.Pair[T, U]) V"},
+		{"Pair[T, context.Context]", generics, edgecases, "generics.Pair[T, context.Context]"},
+		{"types.ID", models, edgecases, "types.ID"},
+		{"RecursiveType", edgecases, edgecases, "RecursiveType"},
+	}
+	for _, test := range tests {
+		got, err := bast.QualifyType(test.expr, test.from, test.to)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.expr, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.expr, test.expected, got)
+		}
+	}
+
+	for _, expr := range []string{"unknown.Type", "[]", "map[string]"} {
+		if _, err := bast.QualifyType(expr, edgecases, to); err == nil {
+			t.Errorf("%s: expected error", expr)
+		}
+	}
+}
+
+// TestQualifyDeclType tests that type parameter names are not qualified
+func TestQualifyDeclType(t *testing.T) {
+	const path = "github.com/vedranvuk/bast/_testproject/pkg/generics"
+	to := ImportContextFunc(func(pkgPath string) string {
+		if pkgPath == path {
+			return "g"
+		}
+		return importBase(pkgPath)
+	})
+
+	for _, typeChecking := range []bool{true, false} {
+		var bast = loadTestProject(t, false)
+		if !typeChecking {
+			cfg := DefaultConfig()
+			cfg.Dir = "_testproject"
+			cfg.TypeChecking = false
+			var err error
+			if bast, err = Load(cfg, "./pkg/generics"); err != nil {
+				t.Fatalf("Failed to load test project: %v", err)
+			}
+		}
+		var (
+			shadow = bast.PkgFunc(path, "Shadow")
+			rename = bast.PkgMethod(path, "Rename")
+			add    = bast.PkgMethod(path, "Add")
+		)
+		if shadow == nil || rename == nil || add == nil {
+			t.Fatal("Expected to find Shadow, Rename and Add")
+		}
+
+		tests := []struct {
+			expr     string
+			decl     Declaration
+			expected string
+			// typed is true if the test requires type information.
+			typed bool
+		}{
+			{"Pair", shadow, "Pair", false},
+			{"context", shadow, "context", false},
+			{"func(Pair) []context", shadow, "func(Pair) []context", false},
+			{"Node[Pair]", shadow, "g.Node[Pair]", false},
+			{"T", add, "T", false},
+			{"*Node[T]", add, "*g.Node[T]", false},
+			{"Pair[T, U]", bast.PkgFunc(path, "Transform"), "g.Pair[T, U]", false},
+			{"*Node[Pair]", rename, "*g.Node[Pair]", true},
+		}
+		for _, test := range tests {
+			if test.typed && !typeChecking {
+				continue
+			}
+			got, err := bast.QualifyDeclType(test.expr, test.decl, to)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.expr, err)
+				continue
+			}
+			if got != test.expected {
+				t.Errorf("%s (type checking %v): expected %q, got %q", test.expr, typeChecking, test.expected, got)
+			}
+		}
+		if got, _ := bast.QualifyType("Pair", shadow.GetFile(), to); got != "g.Pair" {
+			t.Errorf("Expected QualifyType to qualify Pair, got %q", got)
+		}
+	}
+}
+
+// TestImportBase tests assumed package names of import paths
+func TestImportBase(t *testing.T) {
+	for pkgPath, expected := range map[string]string{
+		"strings":                  "strings",
+		"github.com/go-chi/chi/v5": "chi",
+		"gopkg.in/yaml.v3":         "yaml",
+		"example.com/v2":           "example.com",
+		"v2":                       "v2",
+	} {
+		if got := importBase(pkgPath); got != expected {
+			t.Errorf("importBase(%q): expected %q, got %q", pkgPath, expected, got)
+		}
+	}
+}
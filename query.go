@@ -0,0 +1,274 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"go/token"
+	"go/types"
+	"iter"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Query selects top-level declarations of loaded packages matching all of
+// its predicates.
+//
+// Predicate methods add a predicate to the query and return the query so
+// calls can be chained, e.g.:
+//
+//	bast.Query().Kind(DeclStruct).Exported().HasTag("db").Structs()
+//
+// Results are returned in load order.
+type Query struct {
+	// bast is the queried Bast.
+	bast *Bast
+	// packages are the package patterns to match, all packages if empty.
+	packages packagePatterns
+	// preds are the declaration predicates.
+	preds []func(Declaration) bool
+}
+
+// NewQuery returns a new Query over declarations of b matching all
+// declarations.
+func NewQuery(b *Bast) *Query { return &Query{bast: b} }
+
+// Query returns a new Query over declarations of all loaded packages.
+func (self *Bast) Query() *Query { return NewQuery(self) }
+
+// Kind matches declarations of any of the given kinds.
+func (self *Query) Kind(kinds ...DeclKind) *Query {
+	return self.Where(func(decl Declaration) bool {
+		return slices.Contains(kinds, kindOf(decl))
+	})
+}
+
+// Name matches declarations whose name matches any of the path.Match glob
+// patterns, e.g. "New*".
+func (self *Query) Name(patterns ...string) *Query {
+	return self.Where(func(decl Declaration) bool {
+		var name = declName(decl)
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Exported matches exported declarations.
+func (self *Query) Exported() *Query {
+	return self.Where(func(decl Declaration) bool { return token.IsExported(declName(decl)) })
+}
+
+// Unexported matches unexported declarations.
+func (self *Query) Unexported() *Query {
+	return self.Where(func(decl Declaration) bool { return !token.IsExported(declName(decl)) })
+}
+
+// InPackage matches declarations of packages matching any of go list style
+// package patterns, given as import paths or relative to the package
+// module, e.g. "./pkg/..." or "github.com/org/repo/pkg/...".
+func (self *Query) InPackage(patterns ...string) *Query {
+	var trimmed = make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		trimmed = append(trimmed, strings.TrimPrefix(pattern, "./"))
+	}
+	self.packages = append(self.packages, compilePackagePatterns(trimmed)...)
+	return self
+}
+
+// HasTag matches structs with at least one field with a struct tag key.
+func (self *Query) HasTag(key string) *Query {
+	return self.Where(func(decl Declaration) bool {
+		var s, ok = decl.(*Struct)
+		if !ok {
+			return false
+		}
+		for _, field := range s.Fields.Values() {
+			if _, ok := fieldTag(field, key); ok {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// HasDirective matches declarations whose doc comment contains a directive
+// comment with name, e.g. "bast:entity" for "//bast:entity".
+func (self *Query) HasDirective(name string) *Query {
+	return self.Where(func(decl Declaration) bool { return hasDirective(declDoc(decl), name) })
+}
+
+// Implements matches structs and types that implement interface intf by
+// value or pointer.
+//
+// This predicate requires Config.TypeChecking to be enabled.
+func (self *Query) Implements(intf *Interface) *Query { return self.Where(implementsPred(intf)) }
+
+// Where matches declarations for which pred returns true.
+func (self *Query) Where(pred func(Declaration) bool) *Query {
+	self.preds = append(self.preds, pred)
+	return self
+}
+
+// Seq returns an iterator over declarations matching the query.
+func (self *Query) Seq() iter.Seq[Declaration] {
+	return func(yield func(Declaration) bool) {
+		for pkg := range self.bast.PackagesSeq() {
+			if len(self.packages) > 0 && self.packages.match(pkg.Path, pkgModule(pkg)) < 0 {
+				continue
+			}
+		decls:
+			for decl := range pkg.ownDecls() {
+				for _, pred := range self.preds {
+					if !pred(decl) {
+						continue decls
+					}
+				}
+				if !yield(decl) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// All returns declarations matching the query.
+func (self *Query) All() (out []Declaration) { return slices.Collect(self.Seq()) }
+
+// First returns the first declaration matching the query or nil if none
+// match.
+func (self *Query) First() Declaration {
+	for decl := range self.Seq() {
+		return decl
+	}
+	return nil
+}
+
+// Count returns the number of declarations matching the query.
+func (self *Query) Count() (out int) {
+	for range self.Seq() {
+		out++
+	}
+	return
+}
+
+// Vars returns variables matching the query.
+func (self *Query) Vars() []*Var { return slices.Collect(declsSeq[*Var](self.Seq())) }
+
+// Consts returns constants matching the query.
+func (self *Query) Consts() []*Const { return slices.Collect(declsSeq[*Const](self.Seq())) }
+
+// Funcs returns functions matching the query.
+func (self *Query) Funcs() []*Func { return slices.Collect(declsSeq[*Func](self.Seq())) }
+
+// Methods returns methods matching the query.
+func (self *Query) Methods() []*Method { return slices.Collect(declsSeq[*Method](self.Seq())) }
+
+// Types returns types matching the query.
+func (self *Query) Types() []*Type { return slices.Collect(declsSeq[*Type](self.Seq())) }
+
+// Structs returns structs matching the query.
+func (self *Query) Structs() []*Struct { return slices.Collect(declsSeq[*Struct](self.Seq())) }
+
+// Interfaces returns interfaces matching the query.
+func (self *Query) Interfaces() []*Interface {
+	return slices.Collect(declsSeq[*Interface](self.Seq()))
+}
+
+// implementsPred returns a predicate matching structs and types that
+// implement intf by value or pointer.
+func implementsPred(intf *Interface) func(Declaration) bool {
+	var it *types.Interface
+	if named := namedOf(objectOf(intf)); named != nil {
+		it, _ = named.Underlying().(*types.Interface)
+	}
+	return func(decl Declaration) bool {
+		switch decl.(type) {
+		case *Struct, *Type:
+		default:
+			return false
+		}
+		var named = namedOf(objectOf(decl))
+		if it == nil || named == nil || named.TypeParams().Len() > 0 {
+			return false
+		}
+		return types.Implements(named, it) || types.Implements(types.NewPointer(named), it)
+	}
+}
+
+// declName returns the name of decl.
+func declName(decl Declaration) string {
+	if m := modelOf(decl); m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// declDoc returns the doc comment of decl.
+func declDoc(decl Declaration) []string {
+	if m := modelOf(decl); m != nil {
+		return m.Doc
+	}
+	return nil
+}
+
+// modelOf returns the Model of decl or nil if decl is not a bast
+// declaration.
+func modelOf(decl Declaration) *Model {
+	switch d := decl.(type) {
+	case *Var:
+		return &d.Model
+	case *Const:
+		return &d.Model
+	case *Func:
+		return &d.Model
+	case *Method:
+		return &d.Model
+	case *Type:
+		return &d.Model
+	case *Struct:
+		return &d.Model
+	case *Interface:
+		return &d.Model
+	case *Field:
+		return &d.Model
+	}
+	return nil
+}
+
+// pkgModule returns the module path of pkg or an empty string if unknown.
+func pkgModule(pkg *Package) string {
+	if pkg.pkg != nil && pkg.pkg.Module != nil {
+		return pkg.pkg.Module.Path
+	}
+	return ""
+}
+
+// fieldTag returns the value of struct tag key of field and true if the
+// field tag contains key.
+func fieldTag(field *Field, key string) (string, bool) {
+	var tag = field.Tag
+	if unquoted, err := strconv.Unquote(tag); err == nil {
+		tag = unquoted
+	}
+	return reflect.StructTag(tag).Lookup(key)
+}
+
+// hasDirective returns true if doc contains a directive comment with name,
+// i.e. "//name" optionally followed by arguments.
+func hasDirective(doc []string, name string) bool {
+	for _, line := range doc {
+		var rest, ok = strings.CutPrefix(line, "//"+name)
+		if ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			return true
+		}
+	}
+	return false
+}
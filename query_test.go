@@ -0,0 +1,77 @@
+package bast
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestQuery tests composable declaration queries
+func TestQuery(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/entities")
+	if pkg == nil {
+		t.Fatal("Expected to find entities package")
+	}
+
+	names := func(decls []Declaration) string {
+		var out []string
+		for _, decl := range decls {
+			out = append(out, declName(decl))
+		}
+		return strings.Join(out, " ")
+	}
+
+	tests := []struct {
+		name     string
+		query    *Query
+		expected string
+	}{
+		{"Kind", bast.Query().InPackage("./pkg/entities").Kind(DeclStruct), "User Order Draft session"},
+		{"KindMany", bast.Query().InPackage("./pkg/entities").Kind(DeclType, DeclInterface), "Entity Status"},
+		{"Exported", bast.Query().InPackage("./pkg/entities").Kind(DeclStruct).Exported(), "User Order Draft"},
+		{"Unexported", bast.Query().InPackage("./pkg/entities").Unexported(), "session"},
+		{"InPackage", bast.Query().InPackage("pkg/ctors").Name("New*").Kind(DeclFunc), "NewUser NewUserWithGroup NewGroup NewStore NewReader NewHandler"},
+		{"InPackagePath", bast.Query().InPackage("github.com/vedranvuk/bast/_testproject/pkg/...").Name("NewUser"), "NewUser NewUser"},
+		{"HasTag", bast.Query().InPackage("./pkg/entities", "./pkg/ctors").HasTag("db"), "User Order Draft"},
+		{"HasTagJSON", bast.Query().HasTag("json").Unexported(), "session"},
+		{"HasDirective", bast.Query().HasDirective("bast:entity"), "User Order session"},
+		{"Implements", bast.Query().Implements(pkg.Interface("Entity")), "User Order"},
+		{"Combined", bast.Query().Kind(DeclStruct).Exported().InPackage("./pkg/...").HasTag("db").HasDirective("bast:entity").Implements(pkg.Interface("Entity")), "User Order"},
+		{"Where", bast.Query().InPackage("./pkg/entities").Where(func(d Declaration) bool { return declName(d) == "Status" }), "Status"},
+	}
+	for _, test := range tests {
+		if got := names(test.query.All()); got != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, got)
+		}
+	}
+
+	t.Run("Results", func(t *testing.T) {
+		q := bast.Query().InPackage("./pkg/entities").HasDirective("bast:entity")
+		if structs := q.Structs(); len(structs) != 3 || structs[0] != pkg.Struct("User") {
+			t.Errorf("Expected 3 structs, got %d", len(structs))
+		}
+		if n := q.Count(); n != 3 {
+			t.Errorf("Expected count 3, got %d", n)
+		}
+		if first := q.First(); first != Declaration(pkg.Struct("User")) {
+			t.Errorf("Expected first User, got %v", first)
+		}
+		if funcs := q.Funcs(); len(funcs) != 0 {
+			t.Errorf("Expected no funcs, got %d", len(funcs))
+		}
+		if first := bast.Query().Name("Missing").First(); first != nil {
+			t.Errorf("Expected no match, got %v", first)
+		}
+	})
+}
+
+// TestHasDirective tests directive comment matching
+func TestHasDirective(t *testing.T) {
+	doc := []string{"// Doc comment.", "//bast:entity users", "//go:generate stringer"}
+	for name, expected := range map[string]bool{"bast:entity": true, "go:generate": true, "bast:ent": false, "bast": false} {
+		if hasDirective(doc, name) != expected {
+			t.Errorf("hasDirective(%q): expected %v", name, expected)
+		}
+	}
+}
@@ -0,0 +1,483 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// QueryError is an error in a textual query.
+type QueryError struct {
+	// Pos is the position of the error in the query.
+	Pos token.Position
+	// Msg is the error message.
+	Msg string
+}
+
+// Error implements error.
+func (self *QueryError) Error() string {
+	return fmt.Sprintf("query:%d:%d: %s", self.Pos.Line, self.Pos.Column, self.Msg)
+}
+
+// QueryString parses and runs textual query and returns the declarations
+// it selects. See [Bast.ParseQuery] for query syntax.
+func (self *Bast) QueryString(query string) ([]Declaration, error) {
+	var q, err = self.ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return q.All(), nil
+}
+
+// ParseQuery parses textual query into a [Query].
+//
+// A query selects declarations of a kind, optionally filtered by a
+// condition:
+//
+//	structs where pkg ~ "models" and field.tag.json exists and not exported
+//
+// Kind is one of decls, vars, consts, funcs, methods, types, structs or
+// interfaces. Conditions are combined with and, or, not and parentheses.
+// A condition is one of:
+//
+//	exported                  declaration is exported
+//	directive "name"          doc comment contains directive //name
+//	implements "path"         struct or type implements interface at
+//	                          path, resolved using [Bast.Lookup]
+//	name OP "value"           declaration name
+//	pkg OP "value"            package import path, "=" also matches name
+//	file OP "value"           base name of the declaring file
+//	doc OP "value"            doc comment text
+//	type OP "value"           type of a var, const or type
+//	field.name OP "value"     name of any struct field
+//	field.type OP "value"     type of any struct field
+//	field.tag.KEY OP "value"  value of struct tag KEY of any struct field
+//	field.tag.KEY exists      any struct field has struct tag KEY
+//	method.name OP "value"    name of any method of a type or interface
+//
+// OP is one of "=", "!=" or "~", which matches a regular expression.
+//
+// It returns a *[QueryError] with the position of a syntax error.
+func (self *Bast) ParseQuery(query string) (*Query, error) {
+	var p = newQueryParser(self, query)
+	p.next()
+	return p.parseQuery()
+}
+
+// queryKinds maps query kind words to declaration kinds.
+var queryKinds = map[string]DeclKind{
+	"decls":      DeclInvalid,
+	"vars":       DeclVar,
+	"consts":     DeclConst,
+	"funcs":      DeclFunc,
+	"methods":    DeclMethod,
+	"types":      DeclType,
+	"structs":    DeclStruct,
+	"interfaces": DeclInterface,
+}
+
+// queryPred is a query predicate.
+type queryPred = func(Declaration) bool
+
+// queryParser parses a textual query.
+type queryParser struct {
+	bast    *Bast
+	file    *token.File
+	scanner scanner.Scanner
+	// err is the first scanner error.
+	err *QueryError
+
+	pos token.Pos
+	tok token.Token
+	lit string
+}
+
+// newQueryParser returns a new queryParser for query.
+func newQueryParser(bast *Bast, query string) *queryParser {
+	var p = &queryParser{
+		bast: bast,
+		file: token.NewFileSet().AddFile("", -1, len(query)),
+	}
+	p.scanner.Init(p.file, []byte(query), func(pos token.Position, msg string) {
+		if p.err == nil {
+			p.err = &QueryError{pos, msg}
+		}
+	}, 0)
+	return p
+}
+
+// next advances to the next token, skipping automatically inserted
+// semicolons.
+func (self *queryParser) next() {
+	for {
+		self.pos, self.tok, self.lit = self.scanner.Scan()
+		if self.tok != token.SEMICOLON || self.lit != "\n" {
+			return
+		}
+	}
+}
+
+// errorf returns a *QueryError at pos.
+func (self *queryParser) errorf(pos token.Pos, format string, args ...any) error {
+	if self.err != nil {
+		return self.err
+	}
+	return &QueryError{self.file.Position(pos), fmt.Sprintf(format, args...)}
+}
+
+// unexpected returns an error for the current token, expecting what.
+func (self *queryParser) unexpected(what string) error {
+	var found = self.lit
+	switch {
+	case self.tok == token.EOF:
+		found = "end of query"
+	case found == "":
+		found = self.tok.String()
+	}
+	return self.errorf(self.pos, "expected %s, found %s", what, found)
+}
+
+// keyword returns true if the current token is identifier word.
+func (self *queryParser) keyword(word string) bool {
+	return self.ident() && self.lit == word
+}
+
+// ident returns true if the current token is an identifier. Go keywords
+// such as "type" are identifiers in queries.
+func (self *queryParser) ident() bool {
+	return self.tok == token.IDENT || self.tok.IsKeyword()
+}
+
+// parseQuery parses a query.
+func (self *queryParser) parseQuery() (*Query, error) {
+
+	if !self.ident() {
+		return nil, self.unexpected("declaration kind")
+	}
+	var kind, ok = queryKinds[self.lit]
+	if !ok {
+		return nil, self.errorf(self.pos, "unknown declaration kind %s", self.lit)
+	}
+	self.next()
+
+	var q = self.bast.Query()
+	if kind != DeclInvalid {
+		q.Kind(kind)
+	}
+	if self.keyword("where") {
+		self.next()
+		var pred, err = self.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		q.Where(pred)
+	}
+	if self.tok != token.EOF {
+		return nil, self.unexpected("end of query")
+	}
+	if self.err != nil {
+		return nil, self.err
+	}
+
+	return q, nil
+}
+
+// parseOr parses conditions separated by or.
+func (self *queryParser) parseOr() (queryPred, error) {
+	var left, err = self.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for self.keyword("or") {
+		self.next()
+		var right, err = self.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		var l = left
+		left = func(decl Declaration) bool { return l(decl) || right(decl) }
+	}
+	return left, nil
+}
+
+// parseAnd parses conditions separated by and.
+func (self *queryParser) parseAnd() (queryPred, error) {
+	var left, err = self.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for self.keyword("and") {
+		self.next()
+		var right, err = self.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		var l = left
+		left = func(decl Declaration) bool { return l(decl) && right(decl) }
+	}
+	return left, nil
+}
+
+// parseUnary parses a negated, parenthesized or single condition.
+func (self *queryParser) parseUnary() (queryPred, error) {
+
+	if self.keyword("not") {
+		self.next()
+		var pred, err = self.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(decl Declaration) bool { return !pred(decl) }, nil
+	}
+
+	if self.tok == token.LPAREN {
+		self.next()
+		var pred, err = self.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if self.tok != token.RPAREN {
+			return nil, self.unexpected(")")
+		}
+		self.next()
+		return pred, nil
+	}
+
+	return self.parseCondition()
+}
+
+// parseCondition parses a single condition.
+func (self *queryParser) parseCondition() (queryPred, error) {
+
+	if !self.ident() {
+		return nil, self.unexpected("condition")
+	}
+	var pos, name = self.pos, self.lit
+	self.next()
+
+	switch name {
+	case "exported":
+		return func(decl Declaration) bool { return token.IsExported(declName(decl)) }, nil
+	case "directive":
+		var directive, _, err = self.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return func(decl Declaration) bool { return hasDirective(declDoc(decl), directive) }, nil
+	case "implements":
+		var path, valuePos, err = self.parseString()
+		if err != nil {
+			return nil, err
+		}
+		var decl, lookupErr = self.bast.Lookup(path)
+		if lookupErr != nil {
+			return nil, self.errorf(valuePos, "%v", lookupErr)
+		}
+		var intf, ok = decl.(*Interface)
+		if !ok {
+			return nil, self.errorf(valuePos, "%s is not an interface", path)
+		}
+		return implementsPred(intf), nil
+	case "name":
+		return self.parseMatch(func(decl Declaration) []string { return []string{declName(decl)} })
+	case "pkg":
+		return self.parseMatch(func(decl Declaration) []string { return []string{decl.GetPackage().Path} },
+			func(decl Declaration) []string { return []string{decl.GetPackage().Name} })
+	case "file":
+		return self.parseMatch(func(decl Declaration) []string {
+			return []string{filepath.Base(decl.GetFile().Name)}
+		})
+	case "doc":
+		return self.parseMatch(func(decl Declaration) []string {
+			return []string{strings.Join(declDoc(decl), "\n")}
+		})
+	case "type":
+		return self.parseMatch(func(decl Declaration) []string {
+			switch d := decl.(type) {
+			case *Var:
+				return []string{d.Type}
+			case *Const:
+				return []string{d.Type}
+			case *Type:
+				return []string{d.Type}
+			}
+			return nil
+		})
+	case "field":
+		return self.parseFieldCondition(pos)
+	case "method":
+		if err := self.parseSelector("name"); err != nil {
+			return nil, err
+		}
+		return self.parseMatch(func(decl Declaration) (out []string) {
+			switch d := decl.(type) {
+			case *Struct:
+				return methodNames(d.GetPackage(), d.Name)
+			case *Type:
+				return methodNames(d.GetPackage(), d.Name)
+			case *Interface:
+				return d.Methods.Keys()
+			}
+			return
+		})
+	}
+
+	return nil, self.errorf(pos, "unknown condition %s", name)
+}
+
+// parseFieldCondition parses a struct field condition following "field"
+// at pos.
+func (self *queryParser) parseFieldCondition(pos token.Pos) (queryPred, error) {
+
+	if self.tok != token.PERIOD {
+		return nil, self.unexpected(".")
+	}
+	self.next()
+	if !self.ident() {
+		return nil, self.unexpected("name, type or tag")
+	}
+	var attr = self.lit
+	self.next()
+
+	var values func(*Field) []string
+	switch attr {
+	case "name":
+		values = func(f *Field) []string { return []string{f.Name} }
+	case "type":
+		values = func(f *Field) []string { return []string{f.Type} }
+	case "tag":
+		if self.tok != token.PERIOD {
+			return nil, self.unexpected(".")
+		}
+		self.next()
+		if !self.ident() {
+			return nil, self.unexpected("tag key")
+		}
+		var key = self.lit
+		self.next()
+		if self.keyword("exists") {
+			self.next()
+			return func(decl Declaration) bool { return len(tagValues(decl, key)) > 0 }, nil
+		}
+		return self.parseMatch(func(decl Declaration) []string { return tagValues(decl, key) })
+	default:
+		return nil, self.errorf(pos, "unknown field attribute %s", attr)
+	}
+
+	return self.parseMatch(func(decl Declaration) (out []string) {
+		if s, ok := decl.(*Struct); ok {
+			for _, field := range s.Fields.Values() {
+				out = append(out, values(field)...)
+			}
+		}
+		return
+	})
+}
+
+// parseSelector parses "." followed by identifier name.
+func (self *queryParser) parseSelector(name string) error {
+	if self.tok != token.PERIOD {
+		return self.unexpected(".")
+	}
+	self.next()
+	if !self.keyword(name) {
+		return self.unexpected(name)
+	}
+	self.next()
+	return nil
+}
+
+// parseMatch parses an operator and a value and returns a predicate
+// matching declarations for which any of values returned by any of
+// valueFuncs match the value.
+//
+// For "=" and "!=", values of all valueFuncs are compared, for "~" only
+// values of the first one.
+func (self *queryParser) parseMatch(valueFuncs ...func(Declaration) []string) (queryPred, error) {
+
+	var op = self.tok
+	switch op {
+	case token.ASSIGN, token.EQL, token.NEQ, token.TILDE:
+	default:
+		return nil, self.unexpected("=, != or ~")
+	}
+	self.next()
+
+	var value, valuePos, err = self.parseString()
+	if err != nil {
+		return nil, err
+	}
+
+	var match func(string) bool
+	switch op {
+	case token.TILDE:
+		var re, err = regexp.Compile(value)
+		if err != nil {
+			return nil, self.errorf(valuePos, "invalid regular expression: %v", err)
+		}
+		match, valueFuncs = re.MatchString, valueFuncs[:1]
+	default:
+		match = func(s string) bool { return s == value }
+	}
+
+	var matches = func(decl Declaration) bool {
+		for _, values := range valueFuncs {
+			for _, v := range values(decl) {
+				if match(v) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if op == token.NEQ {
+		return func(decl Declaration) bool { return !matches(decl) }, nil
+	}
+	return matches, nil
+}
+
+// parseString parses a string literal and returns its unquoted value and
+// position.
+func (self *queryParser) parseString() (string, token.Pos, error) {
+	if self.tok != token.STRING {
+		return "", self.pos, self.unexpected("string")
+	}
+	var pos = self.pos
+	var value, err = strconv.Unquote(self.lit)
+	if err != nil {
+		return "", pos, self.errorf(pos, "invalid string %s", self.lit)
+	}
+	self.next()
+	return value, pos, nil
+}
+
+// tagValues returns values of struct tag key of fields of decl if decl is
+// a struct.
+func tagValues(decl Declaration, key string) (out []string) {
+	var s, ok = decl.(*Struct)
+	if !ok {
+		return
+	}
+	for _, field := range s.Fields.Values() {
+		if value, ok := fieldTag(field, key); ok {
+			out = append(out, value)
+		}
+	}
+	return
+}
+
+// methodNames returns names of methods of type typeName declared in pkg.
+func methodNames(pkg *Package, typeName string) (out []string) {
+	for _, m := range pkg.index().receivers[typeName] {
+		out = append(out, m.Name)
+	}
+	return
+}
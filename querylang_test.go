@@ -0,0 +1,88 @@
+package bast
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestQueryString tests the textual query language
+func TestQueryString(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	names := func(decls []Declaration) string {
+		var out []string
+		for _, decl := range decls {
+			out = append(out, declName(decl))
+		}
+		return strings.Join(out, " ")
+	}
+
+	t.Run("Queries", func(t *testing.T) {
+		tests := []struct {
+			query    string
+			expected string
+		}{
+			{`structs where pkg ~ "entities" and field.tag.json exists and not exported`, "session"},
+			{`structs where pkg = "entities"`, "User Order Draft session"},
+			{`structs where field.tag.db exists and pkg = "entities"`, "User Order Draft"},
+			{`structs where field.tag.db = "body"`, "Draft"},
+			{`structs where field.tag.db != "id" and pkg = "entities"`, "Draft session"},
+			{`structs where pkg = "entities" and (field.name = "Total" or field.type = "float64")`, "Order"},
+			{`decls where directive "bast:entity"`, "User Order session"},
+			{`types where pkg = "entities"`, "Status"},
+			{`decls where implements "entities.Entity"`, "User Order"},
+			{`funcs where name ~ "^New" and pkg = "entities"`, "NewUser"},
+			{`structs where method.name = "TableName"`, "User Order"},
+			{`interfaces where method.name = "TableName"`, "Entity"},
+			{`decls where file = "order.go"`, "TableName"},
+			{`structs where doc ~ "pointer" and pkg = "entities"`, "Order"},
+			{`structs where pkg = "entities" and not (exported and field.tag.json exists)`, "Order Draft session"},
+			{`types where type = "int" and pkg = "entities"`, "Status"},
+			{"structs\nwhere directive \"bast:entity\"\n  and exported", "User Order"},
+		}
+		for _, test := range tests {
+			decls, err := bast.QueryString(test.query)
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.query, err)
+				continue
+			}
+			if got := names(decls); got != test.expected {
+				t.Errorf("%s: expected %q, got %q", test.query, test.expected, got)
+			}
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			query  string
+			line   int
+			column int
+			msg    string
+		}{
+			{``, 1, 1, "expected declaration kind, found end of query"},
+			{`widgets`, 1, 1, "unknown declaration kind widgets"},
+			{`structs where`, 1, 14, "expected condition, found end of query"},
+			{`structs where size = "1"`, 1, 15, "unknown condition size"},
+			{`structs where name "User"`, 1, 20, "expected =, != or ~, found \"User\""},
+			{`structs where name = User`, 1, 22, "expected string, found User"},
+			{`structs where name ~ "("`, 1, 22, "invalid regular expression"},
+			{`structs where (exported`, 1, 24, "expected ), found end of query"},
+			{`structs exported`, 1, 9, "expected end of query, found exported"},
+			{"structs where\n  field.size = \"1\"", 2, 3, "unknown field attribute size"},
+			{`structs where implements "entities.User"`, 1, 26, "entities.User is not an interface"},
+			{`structs where name = "unterminated`, 1, 22, "string literal not terminated"},
+		}
+		for _, test := range tests {
+			_, err := bast.QueryString(test.query)
+			var qe *QueryError
+			if !errors.As(err, &qe) {
+				t.Errorf("%q: expected QueryError, got %v", test.query, err)
+				continue
+			}
+			if qe.Pos.Line != test.line || qe.Pos.Column != test.column || !strings.Contains(qe.Msg, test.msg) {
+				t.Errorf("%q: expected %d:%d: %s, got %v", test.query, test.line, test.column, test.msg, err)
+			}
+		}
+	})
+}
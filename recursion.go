@@ -0,0 +1,96 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"go/types"
+	"slices"
+)
+
+// TypeCycle is a group of type declarations that depend on each other,
+// directly or through other types of the group, i.e. a strongly connected
+// component of the type dependency graph.
+type TypeCycle struct {
+	// Types are the type declarations of the cycle in load order.
+	Types []Declaration
+	// Fields are the fields of structs in Types whose types depend on a
+	// type of the cycle, i.e. the fields creating the cycle.
+	Fields []*Field
+}
+
+// RecursiveTypes returns the recursive and mutually recursive type
+// declarations of all loaded packages, ordered by their first type.
+//
+// Dependencies between types are those returned by
+// [Bast.TypeDependencies], including dependencies of a type on itself.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Bast) RecursiveTypes() []*TypeCycle {
+	self.typeCyclesOnce.Do(func() {
+		var nodes []Declaration
+		for _, pkg := range self.packages.Values() {
+			for _, file := range pkg.ownFiles() {
+				for _, decl := range file.Declarations.Values() {
+					if _, ok := objectOf(decl).(*types.TypeName); ok {
+						nodes = append(nodes, decl)
+					}
+				}
+			}
+		}
+		var edges = func(decl Declaration) []Declaration {
+			return self.typeDependencies(decl, true)
+		}
+		for _, scc := range stronglyConnected(nodes, edges) {
+			var cycle = &TypeCycle{Types: scc}
+			for _, decl := range scc {
+				var s, ok = decl.(*Struct)
+				if !ok {
+					continue
+				}
+				for _, field := range s.Fields.Values() {
+					for _, dep := range self.TypeDependencies(field) {
+						if slices.Contains(scc, dep) {
+							cycle.Fields = append(cycle.Fields, field)
+							break
+						}
+					}
+				}
+			}
+			self.typeCycles = append(self.typeCycles, cycle)
+		}
+	})
+	return self.typeCycles
+}
+
+// IsRecursive returns true if the struct depends on itself, directly or
+// through other types.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Struct) IsRecursive() bool { return self.typeCycle() != nil }
+
+// RecursiveFields returns the fields of the struct that create a type
+// cycle the struct is part of or nil if the struct is not recursive.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Struct) RecursiveFields() (out []*Field) {
+	if cycle := self.typeCycle(); cycle != nil {
+		for _, field := range cycle.Fields {
+			if slices.Contains(self.Fields.Values(), field) {
+				out = append(out, field)
+			}
+		}
+	}
+	return
+}
+
+// typeCycle returns the type cycle the struct is part of or nil if none.
+func (self *Struct) typeCycle() *TypeCycle {
+	for _, cycle := range self.GetPackage().bast.RecursiveTypes() {
+		if slices.Contains(cycle.Types, Declaration(self)) {
+			return cycle
+		}
+	}
+	return nil
+}
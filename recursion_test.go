@@ -0,0 +1,92 @@
+package bast
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRecursiveTypes tests detection of recursive types
+func TestRecursiveTypes(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	const prefix = "github.com/vedranvuk/bast/_testproject/pkg/"
+
+	var (
+		deps      = bast.PackageByPath(prefix + "deps")
+		edgecases = bast.PackageByPath(prefix + "edgecases")
+		generics  = bast.PackageByPath(prefix + "generics")
+	)
+	if deps == nil || edgecases == nil || generics == nil {
+		t.Fatal("Expected to find test packages")
+	}
+
+	t.Run("Cycles", func(t *testing.T) {
+		cycles := map[string]*TypeCycle{}
+		for _, cycle := range bast.RecursiveTypes() {
+			if pkg := cycle.Types[0].GetPackage(); pkg != deps && pkg != edgecases && pkg != generics {
+				continue
+			}
+			var types []string
+			for _, decl := range cycle.Types {
+				types = append(types, qualifiedName(decl))
+			}
+			cycles[strings.Join(types, " ")] = cycle
+		}
+		expected := []string{
+			prefix + "deps.Employee " + prefix + "deps.Department",
+			prefix + "deps.Tree",
+			prefix + "edgecases.RecursiveType",
+			prefix + "edgecases.Container",
+			prefix + "generics.Node",
+			prefix + "generics.SelfRef",
+		}
+		if len(cycles) != len(expected) {
+			t.Errorf("Expected %d cycles, got %d", len(expected), len(cycles))
+		}
+		for _, name := range expected {
+			if cycles[name] == nil {
+				t.Errorf("Expected cycle %s", name)
+			}
+		}
+
+		if cycle := cycles[expected[0]]; cycle != nil && len(cycle.Fields) != 3 {
+			t.Errorf("Expected 3 fields creating the Employee/Department cycle, got %d", len(cycle.Fields))
+		}
+	})
+
+	t.Run("IsRecursive", func(t *testing.T) {
+		tests := []struct {
+			s        *Struct
+			expected bool
+			fields   []string
+		}{
+			{deps.Struct("Employee"), true, []string{"Department"}},
+			{deps.Struct("Department"), true, []string{"Head", "Staff"}},
+			{deps.Struct("Customer"), false, nil},
+			{edgecases.Struct("Container"), true, []string{"Next"}},
+			{edgecases.Struct("Pair"), false, nil},
+			{generics.Struct("Node"), true, []string{"Next", "Children"}},
+		}
+		for _, test := range tests {
+			if test.s == nil {
+				t.Fatal("Expected to find struct")
+			}
+			if recursive := test.s.IsRecursive(); recursive != test.expected {
+				t.Errorf("%s: expected IsRecursive %v", test.s.Name, test.expected)
+			}
+			var fields []string
+			for _, field := range test.s.RecursiveFields() {
+				fields = append(fields, field.Name)
+			}
+			if len(fields) != len(test.fields) {
+				t.Errorf("%s: expected recursive fields %v, got %v", test.s.Name, test.fields, fields)
+				continue
+			}
+			for i := range fields {
+				if fields[i] != test.fields[i] {
+					t.Errorf("%s: expected recursive fields %v, got %v", test.s.Name, test.fields, fields)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,818 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TypeKind is the kind of an underlying type resolved by ResolveType.
+type TypeKind int
+
+const (
+	// KindInvalid is an unresolved type.
+	KindInvalid TypeKind = iota
+	// KindBasic is a basic type such as int or string.
+	KindBasic
+	// KindPointer is a pointer type.
+	KindPointer
+	// KindSlice is a slice type.
+	KindSlice
+	// KindArray is an array type.
+	KindArray
+	// KindMap is a map type.
+	KindMap
+	// KindChan is a channel type.
+	KindChan
+	// KindFunc is a func type.
+	KindFunc
+	// KindStruct is a struct type.
+	KindStruct
+	// KindInterface is an interface type.
+	KindInterface
+	// KindTypeParam is a type parameter.
+	KindTypeParam
+)
+
+// String returns the kind name.
+func (self TypeKind) String() string {
+	switch self {
+	case KindBasic:
+		return "basic"
+	case KindPointer:
+		return "pointer"
+	case KindSlice:
+		return "slice"
+	case KindArray:
+		return "array"
+	case KindMap:
+		return "map"
+	case KindChan:
+		return "chan"
+	case KindFunc:
+		return "func"
+	case KindStruct:
+		return "struct"
+	case KindInterface:
+		return "interface"
+	case KindTypeParam:
+		return "typeparam"
+	}
+	return "invalid"
+}
+
+// ResolvedType is a type expression resolved by ResolveType.
+//
+// Type names in Type, Underlying and Named are qualified by full import
+// paths, e.g. "github.com/org/repo/pkg.ID".
+type ResolvedType struct {
+	// Type is the resolved type.
+	Type string
+	// Underlying is the underlying type of Type.
+	Underlying string
+	// Kind is the kind of the underlying type.
+	Kind TypeKind
+	// Basic is the name of the basic type if Kind is KindBasic, e.g. "int".
+	Basic string
+	// Pointer is true if Kind is KindPointer.
+	Pointer bool
+	// Len is the array length if Kind is KindArray.
+	Len int64
+	// Elem is the element type if Kind is KindPointer, KindSlice,
+	// KindArray, KindMap or KindChan.
+	Elem *ResolvedType
+	// Key is the key type if Kind is KindMap.
+	Key *ResolvedType
+	// Named is the chain of named types from Type to the last named type
+	// it is defined by, e.g. ["pkg.Level", "pkg.Base"] for "Level" declared
+	// as "type Level Base", "type Base int". Aliases are resolved. It is
+	// empty if Type is not a named type.
+	//
+	// The chain is followed through types declared in loaded packages.
+	Named []string
+	// Package is the import path of the package defining Type, empty if
+	// Type is not a named type or is predeclared.
+	Package string
+	// Decl is the declaration of Type if it is a named type declared in a
+	// loaded package, otherwise nil.
+	Decl Declaration
+}
+
+// ResolveType resolves type expression expr in the scope of loaded packages.
+//
+// Expr may be any type expression, e.g. "Level", "*pkg.Type",
+// "map[string][]pkg.ID" or "List[int]". Unqualified names are looked up in
+// loaded packages then in predeclared types. Qualified names are qualified
+// by the package name of a loaded package or, if no loaded package has that
+// name, of a package imported by a loaded package.
+//
+// It returns an error wrapping [ErrAmbiguous] if an unqualified name is
+// declared in more than one loaded package or if a qualifier names more
+// than one package.
+//
+// Names other than predeclared types are resolved only if
+// Config.TypeChecking is enabled.
+func (self *Bast) ResolveType(expr string) (*ResolvedType, error) {
+	var r = &typeResolver{bast: self}
+	r.ident = func(name string) (types.Object, error) {
+		var (
+			obj   types.Object
+			paths []string
+		)
+		for _, p := range self.packages.Values() {
+			if p.ID != p.Path || p.pkg == nil || p.pkg.Types == nil {
+				continue
+			}
+			if o := p.pkg.Types.Scope().Lookup(name); o != nil {
+				obj, paths = o, append(paths, p.Path)
+			}
+		}
+		switch len(paths) {
+		case 0:
+			return types.Universe.Lookup(name), nil
+		case 1:
+			return obj, nil
+		}
+		return nil, fmt.Errorf("%s is %w, declared in %s", name, ErrAmbiguous, strings.Join(paths, ", "))
+	}
+	r.pkgName = func(name string) (*types.Package, error) {
+		var found []*types.Package
+		for _, p := range self.packages.Values() {
+			if p.ID == p.Path && p.pkg != nil && p.pkg.Types != nil && p.pkg.Types.Name() == name {
+				found = append(found, p.pkg.Types)
+			}
+		}
+		if len(found) == 0 {
+			for _, p := range self.packages.Values() {
+				if p.pkg == nil || p.pkg.Types == nil {
+					continue
+				}
+				for _, imp := range p.pkg.Types.Imports() {
+					if imp.Name() == name && !slices.Contains(found, imp) {
+						found = append(found, imp)
+					}
+				}
+			}
+		}
+		switch len(found) {
+		case 0:
+			return nil, nil
+		case 1:
+			return found[0], nil
+		}
+		var paths []string
+		for _, p := range found {
+			paths = append(paths, p.Path())
+		}
+		return nil, fmt.Errorf("package %s is %w: %s", name, ErrAmbiguous, strings.Join(paths, ", "))
+	}
+	return r.resolve(expr)
+}
+
+// ResolveBasicType resolves the underlying type name for the given typeName
+// by searching the type hierarchy of the parsed packages.
+//
+// If typeName is already a basic type name, it returns typeName as is.
+// If typeName cannot be resolved, it returns an empty string.
+//
+// It is a shorthand for [Bast.ResolveType] returning
+// [ResolvedType.Underlying].
+func (self *Bast) ResolveBasicType(typeName string) string {
+	var rt, err = self.ResolveType(typeName)
+	if err != nil {
+		return ""
+	}
+	return rt.Underlying
+}
+
+// ResolveType resolves type expression expr in the scope of the declaration.
+//
+// Unqualified names are looked up in type parameters of the declaration,
+// or of the declaration the model is a field, parameter or interface method
+// of, the declaration package, dot-imported packages and predeclared types.
+// Qualified names are qualified by imports of the declaration file,
+// including aliased imports. If the model has no file only predeclared
+// types are resolved.
+//
+// Names other than predeclared types are resolved only if
+// Config.TypeChecking is enabled.
+//
+// See [Bast.ResolveType] for details.
+func (self *Model) ResolveType(expr string) (*ResolvedType, error) {
+	return self.resolver().resolve(expr)
+}
+
+// ResolveBasicType resolves the underlying type name for the given typeName
+// by searching the type hierarchy of the parsed packages.
+//
+// If typeName is already a basic type name, it returns typeName as is.
+// If typeName cannot be resolved, it returns an empty string.
+//
+// It is a shorthand for [Model.ResolveType] returning
+// [ResolvedType.Underlying].
+func (self *Model) ResolveBasicType(typeName string) string {
+	var rt, err = self.ResolveType(typeName)
+	if err != nil {
+		return ""
+	}
+	return rt.Underlying
+}
+
+// resolver returns a typeResolver resolving names in the scope of the
+// declaration.
+func (self *Model) resolver() *typeResolver {
+
+	var r = &typeResolver{
+		ident:   func(name string) (types.Object, error) { return types.Universe.Lookup(name), nil },
+		pkgName: func(name string) (*types.Package, error) { return nil, nil },
+	}
+	if self.file == nil {
+		return r
+	}
+
+	var (
+		file    = self.file
+		pkg     = file.pkg
+		tparams = self.typeParams()
+	)
+	if pkg != nil {
+		r.bast = pkg.bast
+		if pkg.pkg != nil {
+			r.pkg = pkg.pkg.Types
+		}
+	}
+
+	r.ident = func(name string) (types.Object, error) {
+		for _, tp := range tparams {
+			if tp.Obj().Name() == name {
+				return tp.Obj(), nil
+			}
+		}
+		if r.pkg != nil {
+			if obj := r.pkg.Scope().Lookup(name); obj != nil {
+				return obj, nil
+			}
+		}
+		for _, imp := range file.Imports.Values() {
+			if imp.Name != "." {
+				continue
+			}
+			if p := file.importedPackage(imp.Path); p != nil {
+				if obj := p.Scope().Lookup(name); obj != nil {
+					return obj, nil
+				}
+			}
+		}
+		return types.Universe.Lookup(name), nil
+	}
+	r.pkgName = func(name string) (*types.Package, error) {
+		if imp := file.importByName(name); imp != nil {
+			return file.importedPackage(imp.Path), nil
+		}
+		return nil, nil
+	}
+
+	return r
+}
+
+// typeParams returns type checker type parameters of the declaration, or
+// of the declaration the model is a field, parameter or interface method
+// of, or nil if it declares none or type checking was not enabled.
+func (self *Model) typeParams() (out []*types.TypeParam) {
+
+	var decl = self.owner()
+	if decl == nil {
+		return nil
+	}
+
+	var add = func(list *types.TypeParamList) {
+		for i := 0; i < list.Len(); i++ {
+			out = append(out, list.At(i))
+		}
+	}
+	switch obj := objectOf(decl).(type) {
+	case *types.TypeName:
+		if named := namedOf(obj); named != nil {
+			add(named.TypeParams())
+		}
+	case *types.Func:
+		add(obj.Signature().RecvTypeParams())
+		add(obj.Signature().TypeParams())
+	}
+
+	return
+}
+
+// owner returns the declaration of the model file whose model is the model
+// or has the model as a field, parameter, result, receiver or interface
+// method, or nil if not found.
+//
+// Declarations are matched by identity, not by name, as names of fields
+// and methods may shadow other declarations of the file.
+func (self *Model) owner() Declaration {
+
+	var has = func(fields ...*FieldMap) bool {
+		for _, m := range fields {
+			for _, f := range m.Values() {
+				if &f.Model == self {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	var hasFunc = func(fn *Func) bool {
+		return &fn.Model == self || has(fn.TypeParams, fn.Params, fn.Results)
+	}
+
+	for _, decl := range self.file.Declarations.Values() {
+		switch d := decl.(type) {
+		case *Func:
+			if hasFunc(d) {
+				return decl
+			}
+		case *Method:
+			if hasFunc(&d.Func) || d.Receiver != nil && &d.Receiver.Model == self {
+				return decl
+			}
+		case *Type:
+			if &d.Model == self || has(d.TypeParams) {
+				return decl
+			}
+		case *Struct:
+			if &d.Model == self || has(d.Fields, d.TypeParams) {
+				return decl
+			}
+		case *Interface:
+			if &d.Model == self || has(d.TypeParams) {
+				return decl
+			}
+			for _, m := range d.Methods.Values() {
+				if hasFunc(&m.Func) {
+					return decl
+				}
+			}
+		default:
+			if modelOf(decl) == self {
+				return decl
+			}
+		}
+	}
+
+	return nil
+}
+
+// importedPackage returns the type checker package imported by the file
+// with import path pkgPath or nil if not found.
+func (self *File) importedPackage(pkgPath string) *types.Package {
+	if self.pkg == nil {
+		return nil
+	}
+	if self.pkg.pkg != nil {
+		if p := self.pkg.pkg.Imports[pkgPath]; p != nil && p.Types != nil {
+			return p.Types
+		}
+	}
+	if self.pkg.bast != nil {
+		if p, ok := self.pkg.bast.packages.Get(pkgPath); ok && p.pkg != nil {
+			return p.pkg.Types
+		}
+	}
+	return nil
+}
+
+// typeResolver resolves type expressions to type checker types.
+type typeResolver struct {
+	// bast is used to find declarations of named types, may be nil.
+	bast *Bast
+	// pkg is the package expressions are resolved in, may be nil.
+	pkg *types.Package
+	// ident returns the object declared by an unqualified name or nil.
+	ident func(name string) (types.Object, error)
+	// pkgName returns the package of a package qualifier or nil.
+	pkgName func(name string) (*types.Package, error)
+}
+
+// resolve resolves type expression expr.
+func (self *typeResolver) resolve(expr string) (*ResolvedType, error) {
+	var t, err = self.parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	return self.bast.resolvedType(t, make(map[*types.TypeName]bool)), nil
+}
+
+// parse parses and resolves type expression expr, which may be prefixed
+// with "..." for variadic params.
+func (self *typeResolver) parse(expr string) (types.Type, error) {
+
+	var variadic bool
+	if elt, ok := strings.CutPrefix(expr, "..."); ok {
+		expr, variadic = elt, true
+	}
+	var node, err = parser.ParseExpr(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid type expression %q: %w", expr, err)
+	}
+
+	var t types.Type
+	if t, err = self.typeOf(node); err != nil {
+		return nil, err
+	}
+	if variadic {
+		t = types.NewSlice(t)
+	}
+	return t, nil
+}
+
+// typeOf returns the type of type expression expr.
+func (self *typeResolver) typeOf(expr ast.Expr) (types.Type, error) {
+
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		var obj, err = self.object(e)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := obj.(*types.TypeName); !ok {
+			return nil, fmt.Errorf("%s is not a type", types.ExprString(e))
+		}
+		return obj.Type(), nil
+	case *ast.ParenExpr:
+		return self.typeOf(e.X)
+	case *ast.StarExpr:
+		var elem, err = self.typeOf(e.X)
+		if err != nil {
+			return nil, err
+		}
+		return types.NewPointer(elem), nil
+	case *ast.ArrayType:
+		var elem, err = self.typeOf(e.Elt)
+		if err != nil {
+			return nil, err
+		}
+		if e.Len == nil {
+			return types.NewSlice(elem), nil
+		}
+		var n int64
+		if n, err = self.arrayLen(e.Len); err != nil {
+			return nil, err
+		}
+		return types.NewArray(elem, n), nil
+	case *ast.MapType:
+		var key, err = self.typeOf(e.Key)
+		if err != nil {
+			return nil, err
+		}
+		var elem types.Type
+		if elem, err = self.typeOf(e.Value); err != nil {
+			return nil, err
+		}
+		return types.NewMap(key, elem), nil
+	case *ast.ChanType:
+		var elem, err = self.typeOf(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		var dir = types.SendRecv
+		switch e.Dir {
+		case ast.SEND:
+			dir = types.SendOnly
+		case ast.RECV:
+			dir = types.RecvOnly
+		}
+		return types.NewChan(dir, elem), nil
+	case *ast.FuncType:
+		return self.signature(e)
+	case *ast.StructType:
+		return self.structType(e)
+	case *ast.InterfaceType:
+		return self.interfaceType(e)
+	case *ast.IndexExpr:
+		return self.instance(e.X, []ast.Expr{e.Index})
+	case *ast.IndexListExpr:
+		return self.instance(e.X, e.Indices)
+	}
+
+	return nil, fmt.Errorf("unsupported type expression %s", types.ExprString(expr))
+}
+
+// object returns the object named by identifier or selector expression
+// expr.
+func (self *typeResolver) object(expr ast.Expr) (types.Object, error) {
+
+	switch e := expr.(type) {
+	case *ast.Ident:
+		var obj, err = self.ident(e.Name)
+		if err != nil {
+			return nil, err
+		}
+		if obj != nil {
+			return obj, nil
+		}
+	case *ast.SelectorExpr:
+		var x, ok = e.X.(*ast.Ident)
+		if !ok {
+			break
+		}
+		var pkg, err = self.pkgName(x.Name)
+		if err != nil {
+			return nil, err
+		}
+		if pkg == nil {
+			return nil, fmt.Errorf("package %s: %w", x.Name, ErrNotFound)
+		}
+		if obj := pkg.Scope().Lookup(e.Sel.Name); obj != nil {
+			return obj, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", types.ExprString(expr), ErrNotFound)
+}
+
+// arrayLen returns the value of array length expression expr.
+func (self *typeResolver) arrayLen(expr ast.Expr) (int64, error) {
+
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind == token.INT {
+			return strconv.ParseInt(e.Value, 0, 64)
+		}
+	case *ast.Ident, *ast.SelectorExpr:
+		var obj, err = self.object(e)
+		if err != nil {
+			return 0, err
+		}
+		if c, ok := obj.(*types.Const); ok {
+			if n, exact := constant.Int64Val(constant.ToInt(c.Val())); exact {
+				return n, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("unsupported array length %s", types.ExprString(expr))
+}
+
+// instance returns generic type expr instantiated with type arguments args.
+func (self *typeResolver) instance(expr ast.Expr, args []ast.Expr) (types.Type, error) {
+
+	var base, err = self.typeOf(expr)
+	if err != nil {
+		return nil, err
+	}
+	var targs = make([]types.Type, 0, len(args))
+	for _, arg := range args {
+		var t types.Type
+		if t, err = self.typeOf(arg); err != nil {
+			return nil, err
+		}
+		targs = append(targs, t)
+	}
+
+	var t types.Type
+	if t, err = types.Instantiate(nil, base, targs, true); err != nil {
+		return nil, fmt.Errorf("instantiate %s: %w", types.ExprString(expr), err)
+	}
+	return t, nil
+}
+
+// signature returns the signature of func type expr.
+func (self *typeResolver) signature(expr *ast.FuncType) (*types.Signature, error) {
+
+	var params, variadic, err = self.tuple(expr.Params)
+	if err != nil {
+		return nil, err
+	}
+	var results *types.Tuple
+	if results, _, err = self.tuple(expr.Results); err != nil {
+		return nil, err
+	}
+
+	return types.NewSignatureType(nil, nil, nil, params, results, variadic), nil
+}
+
+// tuple returns the params in fields and true if the last param is
+// variadic.
+func (self *typeResolver) tuple(fields *ast.FieldList) (*types.Tuple, bool, error) {
+
+	if fields == nil {
+		return nil, false, nil
+	}
+
+	var (
+		vars     []*types.Var
+		variadic bool
+	)
+	for i, field := range fields.List {
+		var (
+			expr = field.Type
+			t    types.Type
+			err  error
+		)
+		if ellipsis, ok := expr.(*ast.Ellipsis); ok && i == len(fields.List)-1 {
+			expr, variadic = ellipsis.Elt, true
+		}
+		if t, err = self.typeOf(expr); err != nil {
+			return nil, false, err
+		}
+		if variadic {
+			t = types.NewSlice(t)
+		}
+		if len(field.Names) == 0 {
+			vars = append(vars, types.NewParam(token.NoPos, self.pkg, "", t))
+		}
+		for _, name := range field.Names {
+			vars = append(vars, types.NewParam(token.NoPos, self.pkg, name.Name, t))
+		}
+	}
+
+	return types.NewTuple(vars...), variadic, nil
+}
+
+// structType returns the struct type of expr.
+func (self *typeResolver) structType(expr *ast.StructType) (*types.Struct, error) {
+
+	var (
+		fields []*types.Var
+		tags   []string
+	)
+	for _, field := range expr.Fields.List {
+		var t, err = self.typeOf(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		var tag string
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+		if len(field.Names) == 0 {
+			var name = types.ExprString(field.Type)
+			name = name[strings.LastIndexAny(name, "*.")+1:]
+			if i := strings.IndexByte(name, '['); i >= 0 {
+				name = name[:i]
+			}
+			fields = append(fields, types.NewField(token.NoPos, self.pkg, name, t, true))
+			tags = append(tags, tag)
+		}
+		for _, name := range field.Names {
+			fields = append(fields, types.NewField(token.NoPos, self.pkg, name.Name, t, false))
+			tags = append(tags, tag)
+		}
+	}
+
+	return types.NewStruct(fields, tags), nil
+}
+
+// interfaceType returns the interface type of expr.
+func (self *typeResolver) interfaceType(expr *ast.InterfaceType) (*types.Interface, error) {
+
+	var (
+		methods   []*types.Func
+		embeddeds []types.Type
+	)
+	for _, field := range expr.Methods.List {
+		if len(field.Names) == 0 {
+			var t, err = self.typeOf(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			embeddeds = append(embeddeds, t)
+			continue
+		}
+		var ft, ok = field.Type.(*ast.FuncType)
+		if !ok {
+			return nil, fmt.Errorf("unsupported interface element %s", types.ExprString(field.Type))
+		}
+		var sig, err = self.signature(ft)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range field.Names {
+			methods = append(methods, types.NewFunc(token.NoPos, self.pkg, name.Name, sig))
+		}
+	}
+
+	return types.NewInterfaceType(methods, embeddeds).Complete(), nil
+}
+
+// resolvedType returns the ResolvedType of t. Named types in visiting are
+// not expanded to prevent infinite recursion of recursive types.
+func (self *Bast) resolvedType(t types.Type, visiting map[*types.TypeName]bool) *ResolvedType {
+
+	var out = &ResolvedType{
+		Type:       types.TypeString(t, nil),
+		Underlying: types.TypeString(t.Underlying(), nil),
+	}
+
+	var named, _ = types.Unalias(t).(*types.Named)
+	if named != nil {
+		var obj = named.Obj()
+		if obj.Pkg() != nil {
+			out.Package = obj.Pkg().Path()
+		}
+		if self != nil {
+			out.Decl = self.declOf(obj)
+		}
+		out.Named = self.namedChain(named)
+	}
+
+	out.Kind = typeKindOf(t)
+	if named != nil {
+		if visiting[named.Obj()] {
+			return out
+		}
+		visiting[named.Obj()] = true
+		defer delete(visiting, named.Obj())
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		if out.Kind == KindBasic {
+			out.Basic = u.Name()
+		}
+	case *types.Pointer:
+		out.Pointer = true
+		out.Elem = self.resolvedType(u.Elem(), visiting)
+	case *types.Slice:
+		out.Elem = self.resolvedType(u.Elem(), visiting)
+	case *types.Array:
+		out.Len = u.Len()
+		out.Elem = self.resolvedType(u.Elem(), visiting)
+	case *types.Map:
+		out.Key = self.resolvedType(u.Key(), visiting)
+		out.Elem = self.resolvedType(u.Elem(), visiting)
+	case *types.Chan:
+		out.Elem = self.resolvedType(u.Elem(), visiting)
+	}
+
+	return out
+}
+
+// namedChain returns names of named types from named to the last named
+// type it is declared by, following type declarations of loaded packages.
+func (self *Bast) namedChain(named *types.Named) (out []string) {
+
+	var seen = make(map[*types.TypeName]bool)
+	for named != nil && !seen[named.Obj()] {
+		seen[named.Obj()] = true
+		out = append(out, types.TypeString(named, nil))
+		if self == nil {
+			break
+		}
+		var decl, ok = self.declOf(named.Obj()).(*Type)
+		if !ok || decl.IsAlias {
+			break
+		}
+		var t, err = decl.resolver().parse(decl.Type)
+		if err != nil {
+			break
+		}
+		named, _ = types.Unalias(t).(*types.Named)
+	}
+
+	return
+}
+
+// typeKindOf returns the TypeKind of t.
+func typeKindOf(t types.Type) TypeKind {
+
+	if _, ok := types.Unalias(t).(*types.TypeParam); ok {
+		return KindTypeParam
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		if u.Kind() == types.Invalid {
+			return KindInvalid
+		}
+		return KindBasic
+	case *types.Pointer:
+		return KindPointer
+	case *types.Slice:
+		return KindSlice
+	case *types.Array:
+		return KindArray
+	case *types.Map:
+		return KindMap
+	case *types.Chan:
+		return KindChan
+	case *types.Signature:
+		return KindFunc
+	case *types.Struct:
+		return KindStruct
+	case *types.Interface:
+		return KindInterface
+	}
+
+	return KindInvalid
+}
@@ -0,0 +1,241 @@
+package bast
+
+import (
+	"errors"
+	gotypes "go/types"
+	"slices"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// TestResolveType tests structured type resolution
+func TestResolveType(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	const (
+		resolve = "github.com/vedranvuk/bast/_testproject/pkg/resolve"
+		types   = "github.com/vedranvuk/bast/_testproject/pkg/types"
+	)
+	container := bast.PkgStruct(resolve, "Container")
+	if container == nil {
+		t.Fatal("Container not found")
+	}
+
+	t.Run("NamedChain", func(t *testing.T) {
+		for _, expr := range []string{"Priority", "Alias"} {
+			rt, err := container.ResolveType(expr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			chain := []string{resolve + ".Priority", resolve + ".Level", resolve + ".Base"}
+			if !slices.Equal(rt.Named, chain) {
+				t.Errorf("%s: Named = %v, expected %v", expr, rt.Named, chain)
+			}
+			if rt.Kind != KindBasic || rt.Basic != "int" || rt.Package != resolve {
+				t.Errorf("%s: unexpected resolution %+v", expr, rt)
+			}
+			if rt.Decl != bast.PkgType(resolve, "Priority") {
+				t.Errorf("%s: unexpected Decl %v", expr, rt.Decl)
+			}
+		}
+	})
+
+	t.Run("Qualified", func(t *testing.T) {
+		rt, err := container.ResolveType("ids.ID")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rt.Type != types+".ID" || rt.Basic != "int" || rt.Package != types {
+			t.Errorf("unexpected resolution %+v", rt)
+		}
+
+		rt, err = container.ResolveType("Timeout")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(rt.Named, []string{resolve + ".Timeout", "time.Duration"}) || rt.Basic != "int64" {
+			t.Errorf("unexpected resolution %+v", rt)
+		}
+	})
+
+	t.Run("Composite", func(t *testing.T) {
+		rt, err := container.ResolveType("*Index")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rt.Kind != KindPointer || !rt.Pointer || rt.Named != nil {
+			t.Fatalf("unexpected resolution %+v", rt)
+		}
+		index := rt.Elem
+		if index.Kind != KindMap || index.Key.Basic != "string" {
+			t.Fatalf("unexpected Elem %+v", index)
+		}
+		if index.Elem.Kind != KindPointer || index.Elem.Elem.Type != resolve+".Level" {
+			t.Errorf("unexpected map Elem %+v", index.Elem)
+		}
+
+		rt, err = container.ResolveType("IDs")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rt.Kind != KindSlice || rt.Elem.Package != types || rt.Elem.Basic != "int" {
+			t.Errorf("unexpected resolution %+v", rt)
+		}
+
+		rt, err = container.ResolveType("Buffer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rt.Kind != KindArray || rt.Len != 4 || rt.Elem.Basic != "byte" {
+			t.Errorf("unexpected resolution %+v", rt)
+		}
+
+		for expr, kind := range map[string]TypeKind{
+			"Handler":                   KindFunc,
+			"Events":                    KindChan,
+			"...string":                 KindSlice,
+			"func(int) (string, error)": KindFunc,
+			"struct{ ids.ID }":          KindStruct,
+			"interface{ Get() Level }":  KindInterface,
+			"error":                     KindInterface,
+			"List":                      KindSlice,
+		} {
+			rt, err := container.ResolveType(expr)
+			if err != nil {
+				t.Errorf("%s: %v", expr, err)
+				continue
+			}
+			if rt.Kind != kind {
+				t.Errorf("%s: Kind = %s, expected %s", expr, rt.Kind, kind)
+			}
+		}
+	})
+
+	t.Run("Generic", func(t *testing.T) {
+		rt, err := container.ResolveType("Pair[string, ids.ID]")
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := resolve + ".Pair[string, " + types + ".ID]"
+		if rt.Kind != KindStruct || rt.Type != expected || rt.Decl != bast.PkgStruct(resolve, "Pair") {
+			t.Errorf("unexpected resolution %+v", rt)
+		}
+
+		rt, err = container.ResolveType("[]T")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rt.Kind != KindSlice || rt.Elem.Kind != KindTypeParam {
+			t.Errorf("unexpected resolution %+v", rt)
+		}
+
+		if _, err = container.ResolveType("Pair[int]"); err == nil {
+			t.Error("expected error for wrong number of type arguments")
+		}
+	})
+
+	t.Run("Bast", func(t *testing.T) {
+		rt, err := bast.ResolveType("map[types.ID]resolve.Level")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rt.Key.Type != types+".ID" || !slices.Equal(rt.Elem.Named, []string{resolve + ".Level", resolve + ".Base"}) {
+			t.Errorf("unexpected resolution %+v", rt)
+		}
+		if rt, err = bast.ResolveType("time.Duration"); err != nil || rt.Basic != "int64" {
+			t.Errorf("unexpected resolution %+v, %v", rt, err)
+		}
+	})
+
+	t.Run("Shadowed", func(t *testing.T) {
+		box := bast.PkgStruct(resolve, "Box")
+		getter := bast.PkgInterface(resolve, "Getter")
+		put := bast.PkgMethod(resolve, "Put")
+		if box == nil || getter == nil || put == nil {
+			t.Fatal("Box, Getter or Put not found")
+		}
+		for name, model := range map[string]*Model{
+			"field":     &mapValue(box.Fields, "Get").Model,
+			"method":    &mapValue(getter.Methods, "Get").Model,
+			"param":     &mapValue(put.Params, "Get").Model,
+			"receiver":  &put.Model,
+			"interface": &getter.Model,
+		} {
+			rt, err := model.ResolveType("T")
+			if err != nil {
+				t.Errorf("%s: %v", name, err)
+				continue
+			}
+			if rt.Kind != KindTypeParam {
+				t.Errorf("%s: Kind = %s, expected typeparam", name, rt.Kind)
+			}
+		}
+	})
+
+	t.Run("NilFile", func(t *testing.T) {
+		model := &NewField(nil, "x").Model
+		if rt, err := model.ResolveType("[]int"); err != nil || rt.Kind != KindSlice {
+			t.Errorf("unexpected resolution %+v, %v", rt, err)
+		}
+		if _, err := model.ResolveType("Priority"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+		if _, err := model.ResolveType("ids.ID"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Ambiguous", func(t *testing.T) {
+		if _, err := bast.ResolveType("User"); !errors.Is(err, ErrAmbiguous) {
+			t.Errorf("expected ErrAmbiguous, got %v", err)
+		}
+		if _, err := bast.ResolveType("[]*User"); !errors.Is(err, ErrAmbiguous) {
+			t.Errorf("expected ErrAmbiguous, got %v", err)
+		}
+		if rt, err := bast.ResolveType("entities.User"); err != nil || rt.Kind != KindStruct {
+			t.Errorf("unexpected resolution %+v, %v", rt, err)
+		}
+
+		var other = new()
+		for _, path := range []string{"example.com/a/util", "example.com/b/util"} {
+			pkg := NewPackage("util", path, &packages.Package{ID: path, Types: gotypes.NewPackage(path, "util")})
+			pkg.bast = other
+			other.packages.Put(pkg.ID, pkg)
+		}
+		if _, err := other.ResolveType("util.Type"); !errors.Is(err, ErrAmbiguous) {
+			t.Errorf("expected ErrAmbiguous, got %v", err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, expr := range []string{"Unknown", "nope.Type", "ids.Unknown"} {
+			if _, err := container.ResolveType(expr); !errors.Is(err, ErrNotFound) {
+				t.Errorf("%s: expected ErrNotFound, got %v", expr, err)
+			}
+		}
+		for _, expr := range []string{"[", "Size", "Base[int]"} {
+			if _, err := container.ResolveType(expr); err == nil {
+				t.Errorf("%s: expected error", expr)
+			}
+		}
+	})
+
+	t.Run("ResolveBasicType", func(t *testing.T) {
+		tests := map[string]string{
+			"Priority":  "int",
+			"[]string":  "[]string",
+			"ids.ID":    "int",
+			"Unknown":   "",
+			"*Priority": "*" + resolve + ".Priority",
+		}
+		for expr, expected := range tests {
+			if s := container.ResolveBasicType(expr); s != expected {
+				t.Errorf("Model.ResolveBasicType(%q) = %q, expected %q", expr, s, expected)
+			}
+		}
+		if s := bast.ResolveBasicType("Priority"); s != "int" {
+			t.Errorf("Bast.ResolveBasicType(Priority) = %q", s)
+		}
+	})
+}
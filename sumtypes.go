@@ -0,0 +1,231 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// SumType is a sealed interface, an interface with unexported methods
+// which can only be implemented by types of the same package, along with
+// its variants.
+type SumType struct {
+	// Interface is the sealed interface.
+	Interface *Interface
+	// Markers are the unexported methods of the interface, including
+	// methods of embedded interfaces, that seal the interface.
+	Markers []*Method
+	// Variants are the types implementing the interface, in order of
+	// declaration.
+	Variants []*SumVariant
+}
+
+// SumVariant is a type implementing a [SumType].
+type SumVariant struct {
+	// Type is the implementing *Struct, *Type or *Func type declaration.
+	Type Declaration
+	// Pointer is true if only the pointer to Type implements the sum type.
+	Pointer bool
+}
+
+// IncompleteSwitch is a type switch over a [SumType] that does not handle
+// all variants.
+type IncompleteSwitch struct {
+	// Pos is the position of the type switch statement.
+	Pos token.Position
+	// Decl is the func or method containing the type switch.
+	Decl Declaration
+	// SumType is the switched sum type.
+	SumType *SumType
+	// Missing are the variants not handled by any case.
+	Missing []*SumVariant
+}
+
+// SumTypes returns the sealed interfaces of all loaded packages with their
+// variants, in load order.
+//
+// Generic interfaces and generic variants are not supported.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Bast) SumTypes() (out []*SumType) {
+
+	for _, pkg := range self.packages.Values() {
+		var candidates []Declaration
+		for _, file := range pkg.ownFiles() {
+			for _, decl := range file.Declarations.Values() {
+				if _, ok := objectOf(decl).(*types.TypeName); ok {
+					candidates = append(candidates, decl)
+				}
+			}
+		}
+		for _, intf := range pkgDecls[*Interface](pkg.ID, self.packages) {
+			if pkg.isCopy(intf.GetFile()) {
+				continue
+			}
+			if st := self.sumType(intf, candidates); st != nil {
+				out = append(out, st)
+			}
+		}
+	}
+
+	return
+}
+
+// IncompleteTypeSwitches returns type switches in func and method bodies of
+// all loaded packages over sum types returned by [Bast.SumTypes] that do not
+// handle all variants, in order of appearance.
+//
+// A variant is handled by a case of its type, a pointer to its type or an
+// interface it implements. Type switches with a default clause are
+// considered complete.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Bast) IncompleteTypeSwitches() (out []*IncompleteSwitch) {
+
+	var sums = make(map[*types.Named]*SumType)
+	for _, st := range self.SumTypes() {
+		if named := namedOf(objectOf(st.Interface)); named != nil {
+			sums[named] = st
+		}
+	}
+	if len(sums) == 0 {
+		return nil
+	}
+
+	for _, pkg := range self.packages.Values() {
+		if pkg.pkg == nil || pkg.pkg.TypesInfo == nil {
+			continue
+		}
+		var info = pkg.pkg.TypesInfo
+		for _, file := range pkg.ownSyntax() {
+			for _, d := range file.Decls {
+				var fd, ok = d.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				ast.Inspect(fd.Body, func(n ast.Node) bool {
+					var ts, ok = n.(*ast.TypeSwitchStmt)
+					if !ok {
+						return true
+					}
+					var st = sums[namedOf(info.TypeOf(typeSwitchExpr(ts)))]
+					if st == nil {
+						return true
+					}
+					if missing := missingVariants(info, ts, st); len(missing) > 0 {
+						out = append(out, &IncompleteSwitch{
+							Pos:     pkg.pkg.Fset.Position(ts.Pos()),
+							Decl:    self.declOf(info.Defs[fd.Name]),
+							SumType: st,
+							Missing: missing,
+						})
+					}
+					return true
+				})
+			}
+		}
+	}
+
+	return
+}
+
+// sumType returns the sum type of intf with variants from candidates or nil
+// if intf is not a sealed interface.
+func (self *Bast) sumType(intf *Interface, candidates []Declaration) *SumType {
+
+	var named = namedOf(objectOf(intf))
+	if named == nil || named.TypeParams().Len() > 0 {
+		return nil
+	}
+	var it = named.Underlying().(*types.Interface)
+
+	var out = &SumType{Interface: intf}
+	for i := 0; i < it.NumMethods(); i++ {
+		if m := it.Method(i); !m.Exported() {
+			if marker, ok := self.declOf(m).(*Method); ok {
+				out.Markers = append(out.Markers, marker)
+			}
+		}
+	}
+	if len(out.Markers) == 0 {
+		return nil
+	}
+
+	for _, decl := range candidates {
+		var t, ok = objectOf(decl).Type().(*types.Named)
+		if !ok || t.TypeParams().Len() > 0 || types.IsInterface(t) {
+			continue
+		}
+		if types.Implements(t, it) {
+			out.Variants = append(out.Variants, &SumVariant{Type: decl})
+		} else if types.Implements(types.NewPointer(t), it) {
+			out.Variants = append(out.Variants, &SumVariant{Type: decl, Pointer: true})
+		}
+	}
+
+	return out
+}
+
+// typeSwitchExpr returns the expression switched on by type switch ts.
+func typeSwitchExpr(ts *ast.TypeSwitchStmt) ast.Expr {
+	var expr ast.Expr
+	switch s := ts.Assign.(type) {
+	case *ast.AssignStmt:
+		expr = s.Rhs[0]
+	case *ast.ExprStmt:
+		expr = s.X
+	}
+	if ta, ok := expr.(*ast.TypeAssertExpr); ok {
+		return ta.X
+	}
+	return nil
+}
+
+// missingVariants returns the variants of st not handled by type switch ts
+// or nil if ts has a default clause.
+func missingVariants(info *types.Info, ts *ast.TypeSwitchStmt, st *SumType) (out []*SumVariant) {
+
+	var cases []types.Type
+	for _, stmt := range ts.Body.List {
+		var clause = stmt.(*ast.CaseClause)
+		if clause.List == nil {
+			return nil
+		}
+		for _, expr := range clause.List {
+			if t := info.TypeOf(expr); t != nil {
+				cases = append(cases, t)
+			}
+		}
+	}
+
+	for _, variant := range st.Variants {
+		var (
+			t       = objectOf(variant.Type).Type()
+			ptr     = types.NewPointer(t)
+			impl    = t
+			handled bool
+		)
+		if variant.Pointer {
+			impl = ptr
+		}
+		for _, c := range cases {
+			if types.Identical(c, t) || types.Identical(c, ptr) {
+				handled = true
+			} else if it, ok := c.Underlying().(*types.Interface); ok {
+				handled = types.Implements(impl, it)
+			}
+			if handled {
+				break
+			}
+		}
+		if !handled {
+			out = append(out, variant)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,99 @@
+package bast
+
+import (
+	"testing"
+)
+
+// TestSumTypes tests sealed interface discovery and type switch checks
+func TestSumTypes(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/sumtypes")
+	if pkg == nil {
+		t.Fatal("Expected to find sumtypes package")
+	}
+
+	variants := func(st *SumType) (out []string) {
+		for _, v := range st.Variants {
+			var name = qualifiedName(v.Type)[len(pkg.Path)+1:]
+			if v.Pointer {
+				name = "*" + name
+			}
+			out = append(out, name)
+		}
+		return
+	}
+	equal := func(a, b []string) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	sums := bast.SumTypes()
+
+	t.Run("SumTypes", func(t *testing.T) {
+		tests := []struct {
+			intf     string
+			markers  []string
+			variants []string
+		}{
+			{"Shape", []string{"Shape.isShape"}, []string{"Circle", "*Cube", "*Square", "Triangle"}},
+			{"Solid", []string{"Shape.isShape"}, []string{"*Cube"}},
+			{"Event", []string{"Event.isEvent"}, []string{"Start", "Stop"}},
+		}
+		if len(sums) != len(tests) {
+			t.Fatalf("Expected %d sum types, got %d", len(tests), len(sums))
+		}
+		for i, test := range tests {
+			var st = sums[i]
+			if st.Interface != pkg.Interface(test.intf) {
+				t.Errorf("Expected sum type %s, got %s", test.intf, st.Interface.Name)
+				continue
+			}
+			var markers []string
+			for _, m := range st.Markers {
+				markers = append(markers, qualifiedName(m)[len(pkg.Path)+1:])
+			}
+			if !equal(markers, test.markers) {
+				t.Errorf("%s: expected markers %v, got %v", test.intf, test.markers, markers)
+			}
+			if v := variants(st); !equal(v, test.variants) {
+				t.Errorf("%s: expected variants %v, got %v", test.intf, test.variants, v)
+			}
+		}
+	})
+
+	t.Run("IncompleteTypeSwitches", func(t *testing.T) {
+		incomplete := bast.IncompleteTypeSwitches()
+		tests := []struct {
+			fn      string
+			intf    string
+			missing []string
+		}{
+			{"Partial", "Shape", []string{"*Cube", "Triangle"}},
+			{"EventName", "Event", []string{"Stop"}},
+		}
+		if len(incomplete) != len(tests) {
+			t.Fatalf("Expected %d incomplete switches, got %d", len(tests), len(incomplete))
+		}
+		for i, test := range tests {
+			var sw = incomplete[i]
+			if sw.Decl != Declaration(pkg.Func(test.fn)) || sw.SumType.Interface.Name != test.intf {
+				t.Errorf("Unexpected incomplete switch at %s", sw.Pos)
+				continue
+			}
+			if sw.Pos.Line == 0 {
+				t.Errorf("Expected position of switch in %s", test.fn)
+			}
+			if missing := variants(&SumType{Variants: sw.Missing}); !equal(missing, test.missing) {
+				t.Errorf("%s: expected missing %v, got %v", test.fn, test.missing, missing)
+			}
+		}
+	})
+}
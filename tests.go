@@ -0,0 +1,291 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"go/ast"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tests returns all test functions (TestXxx) declared in test files of this
+// package.
+func (self *Package) Tests() []*Func { return self.testFuncs("Test", "T") }
+
+// Benchmarks returns all benchmark functions (BenchmarkXxx) declared in test
+// files of this package.
+func (self *Package) Benchmarks() []*Func { return self.testFuncs("Benchmark", "B") }
+
+// FuzzTargets returns all fuzz targets (FuzzXxx) declared in test files of
+// this package.
+func (self *Package) FuzzTargets() []*Func { return self.testFuncs("Fuzz", "F") }
+
+// Examples returns all example functions (ExampleXxx) declared in test files
+// of this package linked to the declarations they document.
+//
+// Examples are resolved on first call.
+func (self *Package) Examples() []*Example {
+	self.examplesOnce.Do(func() {
+		for _, fn := range self.testFuncs("Example", "") {
+			self.examples = append(self.examples, self.newExample(fn))
+		}
+	})
+	return slices.Clone(self.examples)
+}
+
+// hasCopies returns true if the package is a test variant whose package
+// under test is also loaded. Non-test files of the package are then copies
+// of files of the package under test.
+//
+// Queries over all loaded packages skip copies so that declarations of a
+// package under test are not reported again for its test variant.
+func (self *Package) hasCopies() bool {
+	if self.TestVariantOf != self.Path || self.bast == nil {
+		return false
+	}
+	var _, loaded = self.bast.packages.Get(self.Path)
+	return loaded
+}
+
+// isCopy returns true if file is a copy of a file of the loaded package
+// under test, see hasCopies.
+func (self *Package) isCopy(file *File) bool {
+	return file != nil && !file.IsTest && self.hasCopies()
+}
+
+// ownFiles returns the package files in parse order, except copies of files
+// of the loaded package under test.
+func (self *Package) ownFiles() (out []*File) {
+	for _, file := range self.Files.Values() {
+		if !self.isCopy(file) {
+			out = append(out, file)
+		}
+	}
+	return
+}
+
+// ownSyntax returns the syntax trees of the package, except those of copies
+// of files of the loaded package under test.
+func (self *Package) ownSyntax() (out []*ast.File) {
+	if self.pkg == nil {
+		return nil
+	}
+	var copies = self.hasCopies()
+	for _, file := range self.pkg.Syntax {
+		if copies && !strings.HasSuffix(self.pkg.Fset.Position(file.Package).Filename, "_test.go") {
+			continue
+		}
+		out = append(out, file)
+	}
+	return
+}
+
+// testFuncs returns all funcs from test files of this package whose name
+// follows the go test naming convention for prefix and whose single param
+// is a pointer to testing type typ. If typ is empty funcs must have no params.
+func (self *Package) testFuncs(prefix, typ string) (out []*Func) {
+	for _, file := range self.Files.Values() {
+		if !file.IsTest {
+			continue
+		}
+		for _, decl := range file.Declarations.Values() {
+			var fn, ok = decl.(*Func)
+			if !ok || !isTestName(fn.Name, prefix) || fn.TypeParams.Len() > 0 || fn.Results.Len() > 0 {
+				continue
+			}
+			if typ == "" {
+				if fn.Params.Len() == 0 {
+					out = append(out, fn)
+				}
+				continue
+			}
+			if fn.Params.Len() != 1 {
+				continue
+			}
+			var param = fn.Params.Values()[0]
+			if strings.HasPrefix(param.Type, "*") && strings.HasSuffix(param.Type, "."+typ) {
+				out = append(out, fn)
+			}
+		}
+	}
+	return
+}
+
+// isTestName reports whether name is a test function name for prefix, i.e.
+// "TestXxx" where Xxx does not start with a lowercase letter.
+//
+// It follows the rules of the go test tool.
+func isTestName(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	var r, _ = utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
+// Example represents an example function declared in a test file.
+//
+// Examples are linked to declarations using go/doc naming conventions:
+// "Example" documents the package, "ExampleF" func F, "ExampleT" type T and
+// "ExampleT_M" method M of type T. Any of those may be followed by an
+// underscore and a suffix starting with a lowercase letter.
+type Example struct {
+	// Func is the example function.
+	Func *Func
+	// Name is the name of the documented declaration derived from the
+	// example function name, e.g. "T.M" for "ExampleT_M".
+	//
+	// Name is empty for package examples.
+	Name string
+	// Suffix is the example suffix, e.g. "second" for "ExampleT_second".
+	Suffix string
+	// Target is the declaration the example documents.
+	//
+	// It is nil for package examples or if the documented declaration was
+	// not found in the package under test.
+	Target Declaration
+	// Code is the example function body source without enclosing braces
+	// and without the output comment.
+	//
+	// For whole file examples it is the source of all declarations of the
+	// example file except imports, without the output comment.
+	Code string
+	// Output is the expected output as specified by the example's
+	// "Output:" or "Unordered output:" comment.
+	Output string
+	// Unordered is true if the output is specified by an
+	// "Unordered output:" comment.
+	Unordered bool
+}
+
+// newExample returns a new Example for example function fn declared in this
+// package with its Target resolved.
+//
+// Targets are resolved in the package under test if this package is a test
+// variant and the package under test was loaded.
+func (self *Package) newExample(fn *Func) *Example {
+
+	var ex = &Example{Func: fn}
+	if parsed, ok := fn.file.examples[fn.Name]; ok {
+		ex.Code = parsed.Code
+		ex.Output = parsed.Output
+		ex.Unordered = parsed.Unordered
+	}
+	var name = strings.TrimPrefix(fn.Name, "Example")
+	if i := strings.LastIndexByte(name, '_'); i >= 0 {
+		if r, _ := utf8.DecodeRuneInString(name[i+1:]); unicode.IsLower(r) {
+			ex.Suffix = name[i+1:]
+			name = name[:i]
+		}
+	}
+	ex.Name = strings.Replace(name, "_", ".", 1)
+	if ex.Name == "" {
+		return ex
+	}
+
+	var pkg = self
+	if self.TestVariantOf != "" {
+		if p, ok := self.bast.packages.Get(self.TestVariantOf); ok {
+			pkg = p
+		}
+	}
+
+	var typeName, methodName, isMethod = strings.Cut(ex.Name, ".")
+	if !isMethod {
+		for _, file := range pkg.Files.Values() {
+			if file.IsTest {
+				continue
+			}
+			// A method of the same name may shadow the declaration in
+			// this file, keep looking in other files.
+			if decl, ok := file.Declarations.Get(typeName); ok {
+				if _, isMethod := decl.(*Method); !isMethod {
+					ex.Target = decl
+					return ex
+				}
+			}
+		}
+		return ex
+	}
+
+	for _, file := range pkg.Files.Values() {
+		if file.IsTest {
+			continue
+		}
+		if intf := file.Interface(typeName); intf != nil {
+			if m, ok := intf.Methods.Get(methodName); ok {
+				ex.Target = m
+			}
+			return ex
+		}
+		for _, decl := range file.Declarations.Values() {
+			if m, ok := decl.(*Method); ok && m.Receiver.Type == typeName && m.Name == methodName {
+				ex.Target = m
+				return ex
+			}
+		}
+	}
+
+	return ex
+}
+
+// Examples returns the examples documenting this function.
+func (self *Func) Examples() []*Example { return examplesFor(self) }
+
+// Examples returns the examples documenting this method.
+func (self *Method) Examples() []*Example { return examplesFor(self) }
+
+// Examples returns the examples documenting this type.
+func (self *Type) Examples() []*Example { return examplesFor(self) }
+
+// Examples returns the examples documenting this struct.
+func (self *Struct) Examples() []*Example { return examplesFor(self) }
+
+// Examples returns the examples documenting this interface.
+func (self *Interface) Examples() []*Example { return examplesFor(self) }
+
+// examplesFor returns examples documenting decl declared in the package of
+// decl or any of its loaded test variants.
+//
+// Examples are only available if packages were loaded with [Config.Tests].
+func examplesFor(decl Declaration) (out []*Example) {
+	var pkg = decl.GetPackage()
+	for _, p := range pkg.bast.packages.Values() {
+		if p != pkg && p.TestVariantOf != pkg.Path {
+			continue
+		}
+		for _, ex := range p.Examples() {
+			if ex.Target == decl {
+				out = append(out, ex)
+			}
+		}
+	}
+	return
+}
+
+// exampleCode returns example function body code printed as a block
+// statement with enclosing braces, indentation and output comment removed.
+func exampleCode(block string) string {
+	var code = strings.TrimSpace(block)
+	code = strings.TrimPrefix(code, "{")
+	code = strings.TrimSuffix(code, "}")
+	var lines = strings.Split(code, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		var line = strings.ToLower(lines[i])
+		if strings.HasPrefix(line, "// output:") || strings.HasPrefix(line, "// unordered output:") {
+			lines = lines[:i]
+			break
+		}
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,327 @@
+package bast
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestTestVariants tests loading of test variant packages and test file detection
+func TestTestVariants(t *testing.T) {
+	bast := loadTestProject(t, true)
+
+	const path = "github.com/vedranvuk/bast/_testproject/pkg/models"
+
+	t.Run("PackageVariants", func(t *testing.T) {
+		pkg := bast.PackageByPath(path)
+		if pkg == nil {
+			t.Fatal("Expected to find models package")
+		}
+		if pkg.TestVariantOf != "" {
+			t.Errorf("Expected models package not to be a test variant, got '%s'", pkg.TestVariantOf)
+		}
+		for _, file := range pkg.Files.Values() {
+			if file.IsTest {
+				t.Errorf("Expected no test files in models package, got '%s'", file.Name)
+			}
+		}
+
+		variant := bast.PackageByID(path + " [" + path + ".test]")
+		if variant == nil {
+			t.Fatalf("Expected to find models test variant, got: %v", bast.PackageImportPaths())
+		}
+		if variant.Path != path {
+			t.Errorf("Expected test variant path '%s', got '%s'", path, variant.Path)
+		}
+		if variant.TestVariantOf != path {
+			t.Errorf("Expected test variant of '%s', got '%s'", path, variant.TestVariantOf)
+		}
+		if variant.Func("TestTestFunc1") == nil {
+			t.Error("Expected to find TestTestFunc1 in test variant")
+		}
+
+		external := bast.PackageByID(path + "_test [" + path + ".test]")
+		if external == nil {
+			t.Fatalf("Expected to find external test package, got: %v", bast.PackageImportPaths())
+		}
+		if bast.PackageByPath(path+"_test") != external {
+			t.Error("Expected to find external test package by path")
+		}
+		if bast.PackageByPath(variant.ID) != nil {
+			t.Error("Expected test variant not to be found by ID using PackageByPath")
+		}
+		if bast.MethodSet(variant.ID, "TestStruct1") == nil {
+			t.Error("Expected to find methods of TestStruct1 in test variant by ID")
+		}
+		if external.Name != "models_test" {
+			t.Errorf("Expected external test package name 'models_test', got '%s'", external.Name)
+		}
+		if external.TestVariantOf != path {
+			t.Errorf("Expected external test package variant of '%s', got '%s'", path, external.TestVariantOf)
+		}
+
+		if bast.PackageByPath(path+".test") != nil {
+			t.Error("Expected test executable package to be skipped")
+		}
+	})
+
+	t.Run("TestFuncs", func(t *testing.T) {
+		variant := bast.PackageByID(path + " [" + path + ".test]")
+		if variant == nil {
+			t.Fatal("Expected to find models test variant")
+		}
+		checkNames := func(label string, funcs []*Func, expected ...string) {
+			if len(funcs) != len(expected) {
+				t.Errorf("Expected %d %s, got %d", len(expected), label, len(funcs))
+				return
+			}
+			for i, fn := range funcs {
+				if fn.Name != expected[i] {
+					t.Errorf("Expected %s '%s', got '%s'", label, expected[i], fn.Name)
+				}
+			}
+		}
+		checkNames("tests", variant.Tests(), "TestTestFunc1")
+		checkNames("benchmarks", variant.Benchmarks(), "BenchmarkTestFunc2")
+		checkNames("fuzz targets", variant.FuzzTargets(), "FuzzTestFunc7")
+		if len(variant.Examples()) != 0 {
+			t.Errorf("Expected no examples in test variant, got %d", len(variant.Examples()))
+		}
+	})
+
+	t.Run("Examples", func(t *testing.T) {
+		external := bast.PackageByID(path + "_test [" + path + ".test]")
+		if external == nil {
+			t.Fatal("Expected to find external test package")
+		}
+		pkg := bast.PackageByPath(path)
+
+		examples := map[string]*Example{}
+		for _, ex := range external.Examples() {
+			examples[ex.Func.Name] = ex
+		}
+		if len(examples) != 6 {
+			t.Fatalf("Expected 6 examples, got %d", len(examples))
+		}
+
+		if ex := examples["Example"]; ex.Name != "" || ex.Target != nil {
+			t.Errorf("Expected package example, got name '%s'", ex.Name)
+		}
+		if ex := examples["ExampleTestFunc3"]; ex.Target != pkg.Func("TestFunc3") {
+			t.Error("Expected ExampleTestFunc3 to document TestFunc3")
+		}
+		if ex := examples["ExampleTestStruct1_TestMethod1"]; ex.Name != "TestStruct1.TestMethod1" {
+			t.Errorf("Expected name 'TestStruct1.TestMethod1', got '%s'", ex.Name)
+		} else if m, ok := ex.Target.(*Method); !ok || m.Receiver.Type != "TestStruct1" {
+			t.Error("Expected ExampleTestStruct1_TestMethod1 to document TestStruct1.TestMethod1")
+		}
+		if ex := examples["ExampleTestStruct2_second"]; ex.Suffix != "second" || ex.Target != pkg.Struct("TestStruct2") {
+			t.Errorf("Expected suffixed example of TestStruct2, got suffix '%s'", ex.Suffix)
+		}
+		intf := pkg.Interface("Interface2")
+		method, _ := intf.Methods.Get("IntfMethod1")
+		if ex := examples["ExampleInterface2_IntfMethod1"]; ex.Target != method {
+			t.Error("Expected ExampleInterface2_IntfMethod1 to document Interface2.IntfMethod1")
+		}
+	})
+}
+
+// TestExampleDetails tests example code and output extraction and linking
+func TestExampleDetails(t *testing.T) {
+	bast := loadTestProject(t, true)
+
+	pkg := bast.PackageByPath("github.com/vedranvuk/bast/_testproject/pkg/models")
+	if pkg == nil {
+		t.Fatal("Expected to find models package")
+	}
+
+	t.Run("FuncExamples", func(t *testing.T) {
+		examples := pkg.Func("TestFunc3").Examples()
+		if len(examples) != 1 {
+			t.Fatalf("Expected 1 example for TestFunc3, got %d", len(examples))
+		}
+		ex := examples[0]
+		if ex.Code != "fmt.Println(models.TestFunc3())" {
+			t.Errorf("Unexpected example code: %q", ex.Code)
+		}
+		if ex.Output != "0 <nil>\n" {
+			t.Errorf("Unexpected example output: %q", ex.Output)
+		}
+		if ex.Unordered {
+			t.Error("Expected ordered output")
+		}
+	})
+
+	t.Run("UnorderedOutput", func(t *testing.T) {
+		examples := pkg.Func("TestFunc6").Examples()
+		if len(examples) != 1 {
+			t.Fatalf("Expected 1 example for TestFunc6, got %d", len(examples))
+		}
+		ex := examples[0]
+		if !ex.Unordered {
+			t.Error("Expected unordered output")
+		}
+		if ex.Output != "0\n1\n2\n" {
+			t.Errorf("Unexpected example output: %q", ex.Output)
+		}
+		if strings.Contains(ex.Code, "output") {
+			t.Errorf("Expected output comment to be stripped from code: %q", ex.Code)
+		}
+	})
+
+	t.Run("MethodAndTypeExamples", func(t *testing.T) {
+		var method *Method
+		for _, m := range pkg.Struct("TestStruct1").Methods() {
+			if m.Name == "TestMethod1" {
+				method = m
+			}
+		}
+		if method == nil {
+			t.Fatal("Expected to find TestMethod1")
+		}
+		if examples := method.Examples(); len(examples) != 1 {
+			t.Errorf("Expected 1 example for TestStruct1.TestMethod1, got %d", len(examples))
+		}
+		if examples := pkg.Struct("TestStruct2").Examples(); len(examples) != 1 || examples[0].Suffix != "second" {
+			t.Errorf("Expected 1 suffixed example for TestStruct2, got %d", len(examples))
+		}
+		if examples := pkg.Type("CustomType").Examples(); len(examples) != 0 {
+			t.Errorf("Expected no examples for CustomType, got %d", len(examples))
+		}
+	})
+}
+
+// TestExampleShadowedTarget tests linking examples to declarations shadowed
+// by same-named methods in another file
+func TestExampleShadowedTarget(t *testing.T) {
+	bast := loadTestProject(t, true)
+
+	const path = "github.com/vedranvuk/bast/_testproject/pkg/exampletest"
+	pkg := bast.PackageByPath(path)
+	external := bast.PackageByPath(path + "_test")
+	if pkg == nil || external == nil {
+		t.Fatal("Expected to find exampletest packages")
+	}
+
+	var render *Func
+	for _, fn := range bast.PkgFuncs(path) {
+		if fn.Name == "Render" {
+			render = fn
+		}
+	}
+	if render == nil {
+		t.Fatal("Expected to find func Render")
+	}
+
+	examples := map[string]*Example{}
+	for _, ex := range external.Examples() {
+		examples[ex.Func.Name] = ex
+	}
+	if ex := examples["ExampleRender"]; ex == nil || ex.Target != render {
+		t.Errorf("Expected ExampleRender to document func Render, got %v", ex)
+	}
+	if ex := examples["ExampleShadow_Render"]; ex == nil {
+		t.Error("Expected ExampleShadow_Render")
+	} else if m, ok := ex.Target.(*Method); !ok || m.Receiver.Type != "Shadow" {
+		t.Errorf("Expected ExampleShadow_Render to document Shadow.Render, got %v", ex.Target)
+	}
+
+	if len(render.Examples()) != 1 {
+		t.Error("Expected one example of func Render")
+	}
+	if a, b := external.Examples(), external.Examples(); len(a) != 3 || a[0] != b[0] {
+		t.Error("Expected examples to be resolved once")
+	}
+}
+
+// TestWholeFileExample tests code extraction of whole file examples
+func TestWholeFileExample(t *testing.T) {
+	bast := loadTestProject(t, true)
+
+	const path = "github.com/vedranvuk/bast/_testproject/pkg/exampletest"
+	examples := bast.PkgStruct(path, "Shadow").Examples()
+	if len(examples) != 1 {
+		t.Fatalf("Expected 1 example of Shadow, got %d", len(examples))
+	}
+	expected := `// twice renders r two times.
+func twice(r interface{ Render() string }) string {
+	return r.Render() + " " + r.Render()
+}
+
+func ExampleShadow() {
+	fmt.Println(twice(exampletest.Shadow{}))
+}`
+	if ex := examples[0]; ex.Code != expected {
+		t.Errorf("Unexpected example code:\n%s", ex.Code)
+	}
+	if ex := examples[0]; ex.Output != "shadow shadow\n" {
+		t.Errorf("Unexpected example output: %q", ex.Output)
+	}
+}
+
+// TestTestVariantCopies tests that declarations of a package under test are
+// reported once when loading with tests
+func TestTestVariantCopies(t *testing.T) {
+	plain, tests := loadTestProject(t, false), loadTestProject(t, true)
+
+	counts := func(bast *Bast) map[string]int {
+		return map[string]int{
+			"AllVars":        nonTestCount(bast.AllVars()),
+			"AllConsts":      nonTestCount(bast.AllConsts()),
+			"AllFuncs":       nonTestCount(bast.AllFuncs()),
+			"AllMethods":     nonTestCount(bast.AllMethods()),
+			"AllTypes":       nonTestCount(bast.AllTypes()),
+			"AllStructs":     nonTestCount(bast.AllStructs()),
+			"AllInterfaces":  nonTestCount(bast.AllInterfaces()),
+			"Decls":          nonTestCount(slices.Collect(bast.Decls())),
+			"SumTypes":       len(bast.SumTypes()),
+			"RecursiveTypes": len(bast.RecursiveTypes()),
+			"CallGraph":      nonTestCount(bast.CallGraph().Nodes),
+		}
+	}
+	expected, got := counts(plain), counts(tests)
+	for name, n := range expected {
+		if got[name] != n {
+			t.Errorf("%s: expected %d declarations with tests, got %d", name, n, got[name])
+		}
+	}
+
+	if n := tests.Query().Name("TestStruct1").Count(); n != 1 {
+		t.Errorf("Expected 1 TestStruct1, got %d", n)
+	}
+	if s := tests.AnyStruct("TestStruct1"); s == nil || s.GetPackage().TestVariantOf != "" {
+		t.Error("Expected AnyStruct to return TestStruct1 of the package under test")
+	}
+}
+
+// nonTestCount returns the number of decls not declared in test files.
+func nonTestCount[T Declaration](decls []T) (out int) {
+	for _, decl := range decls {
+		if !decl.GetFile().IsTest {
+			out++
+		}
+	}
+	return
+}
+
+// TestIsTestName tests go test function name classification
+func TestIsTestName(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		expected bool
+	}{
+		{"Test", "Test", true},
+		{"TestFoo", "Test", true},
+		{"Test_foo", "Test", true},
+		{"Testfoo", "Test", false},
+		{"BenchmarkFoo", "Test", false},
+		{"Example", "Example", true},
+		{"ExampleT_m", "Example", true},
+	}
+	for _, test := range tests {
+		if result := isTestName(test.name, test.prefix); result != test.expected {
+			t.Errorf("isTestName(%q, %q) = %v, expected %v", test.name, test.prefix, result, test.expected)
+		}
+	}
+}
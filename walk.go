@@ -0,0 +1,249 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+// WalkAction controls the traversal of [Walk].
+type WalkAction int
+
+const (
+	// WalkContinue continues the walk, visiting children of the element.
+	WalkContinue WalkAction = iota
+	// WalkSkip skips children of the element. Returned from
+	// [Visitor.Leave] it is the same as WalkContinue.
+	WalkSkip
+	// WalkStop stops the walk.
+	WalkStop
+)
+
+// FieldRole is the role of a [Field] visited by [Walk].
+type FieldRole int
+
+const (
+	// FieldStruct is a struct field.
+	FieldStruct FieldRole = iota
+	// FieldTypeParam is a type parameter of a struct, type, interface, func
+	// or method.
+	FieldTypeParam
+	// FieldReceiver is a method receiver.
+	FieldReceiver
+	// FieldParam is a func or method parameter.
+	FieldParam
+	// FieldResult is a func or method result.
+	FieldResult
+)
+
+// Visitor visits elements of the model walked by [Walk].
+//
+// Each Visit method is called before visiting children of the element, in
+// pre-order, and Leave is called with the element after visiting its
+// children, in post-order, unless children were skipped.
+//
+// Embed [BaseVisitor] to implement only some of the methods.
+type Visitor interface {
+	// VisitPackage visits a package. Children are files.
+	VisitPackage(pkg *Package) WalkAction
+	// VisitFile visits a file. Children are imports and declarations.
+	VisitFile(file *File) WalkAction
+	// VisitImport visits an import. It has no children.
+	VisitImport(imp *ImportSpec) WalkAction
+	// VisitVar visits a variable. It has no children.
+	VisitVar(v *Var) WalkAction
+	// VisitConst visits a constant. It has no children.
+	VisitConst(c *Const) WalkAction
+	// VisitFunc visits a func. Children are type parameters, params and
+	// results.
+	VisitFunc(f *Func) WalkAction
+	// VisitMethod visits a method or an interface method. Children are the
+	// receiver, type parameters, params and results.
+	VisitMethod(m *Method) WalkAction
+	// VisitType visits a type. Children are type parameters.
+	VisitType(t *Type) WalkAction
+	// VisitStruct visits a struct. Children are type parameters and
+	// fields.
+	VisitStruct(s *Struct) WalkAction
+	// VisitInterface visits an interface or an embedded interface.
+	// Children are type parameters, methods and embedded interfaces.
+	VisitInterface(i *Interface) WalkAction
+	// VisitField visits a field in role. It has no children.
+	VisitField(f *Field, role FieldRole) WalkAction
+	// Leave is called with an element after its children were visited.
+	Leave(element any) WalkAction
+}
+
+// BaseVisitor is a [Visitor] that visits all elements and does nothing.
+type BaseVisitor struct{}
+
+// VisitPackage implements Visitor.
+func (BaseVisitor) VisitPackage(*Package) WalkAction { return WalkContinue }
+
+// VisitFile implements Visitor.
+func (BaseVisitor) VisitFile(*File) WalkAction { return WalkContinue }
+
+// VisitImport implements Visitor.
+func (BaseVisitor) VisitImport(*ImportSpec) WalkAction { return WalkContinue }
+
+// VisitVar implements Visitor.
+func (BaseVisitor) VisitVar(*Var) WalkAction { return WalkContinue }
+
+// VisitConst implements Visitor.
+func (BaseVisitor) VisitConst(*Const) WalkAction { return WalkContinue }
+
+// VisitFunc implements Visitor.
+func (BaseVisitor) VisitFunc(*Func) WalkAction { return WalkContinue }
+
+// VisitMethod implements Visitor.
+func (BaseVisitor) VisitMethod(*Method) WalkAction { return WalkContinue }
+
+// VisitType implements Visitor.
+func (BaseVisitor) VisitType(*Type) WalkAction { return WalkContinue }
+
+// VisitStruct implements Visitor.
+func (BaseVisitor) VisitStruct(*Struct) WalkAction { return WalkContinue }
+
+// VisitInterface implements Visitor.
+func (BaseVisitor) VisitInterface(*Interface) WalkAction { return WalkContinue }
+
+// VisitField implements Visitor.
+func (BaseVisitor) VisitField(*Field, FieldRole) WalkAction { return WalkContinue }
+
+// Leave implements Visitor.
+func (BaseVisitor) Leave(any) WalkAction { return WalkContinue }
+
+// Walk walks packages of b in load order and their elements in parse
+// order, calling v for each element.
+//
+// Non-test files of a test variant are not walked if the package under test
+// is loaded, as they are walked with that package.
+//
+// It returns false if the walk was stopped by v returning WalkStop.
+func Walk(b *Bast, v Visitor) bool {
+	var w = &walker{v}
+	for _, pkg := range b.packages.Values() {
+		if !w.walk(pkg, w.v.VisitPackage(pkg), func() bool {
+			for _, file := range pkg.ownFiles() {
+				if !w.file(file) {
+					return false
+				}
+			}
+			return true
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// walker walks the model.
+type walker struct {
+	v Visitor
+}
+
+// walk handles action returned by visiting element, walks children of
+// element if action allows it and calls Visitor.Leave. It returns false if
+// the walk is stopped.
+func (self *walker) walk(element any, action WalkAction, children func() bool) bool {
+	switch action {
+	case WalkStop:
+		return false
+	case WalkSkip:
+		return true
+	}
+	if children != nil && !children() {
+		return false
+	}
+	return self.v.Leave(element) != WalkStop
+}
+
+// file walks file.
+func (self *walker) file(file *File) bool {
+	return self.walk(file, self.v.VisitFile(file), func() bool {
+		for _, imp := range file.Imports.Values() {
+			if !self.walk(imp, self.v.VisitImport(imp), nil) {
+				return false
+			}
+		}
+		for _, decl := range file.Declarations.Values() {
+			if !self.decl(decl) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// decl walks declaration decl.
+func (self *walker) decl(decl Declaration) bool {
+	switch d := decl.(type) {
+	case *Var:
+		return self.walk(d, self.v.VisitVar(d), nil)
+	case *Const:
+		return self.walk(d, self.v.VisitConst(d), nil)
+	case *Func:
+		return self.walk(d, self.v.VisitFunc(d), func() bool { return self.signature(d) })
+	case *Method:
+		return self.method(d)
+	case *Type:
+		return self.walk(d, self.v.VisitType(d), func() bool {
+			return self.fields(d.TypeParams, FieldTypeParam)
+		})
+	case *Struct:
+		return self.walk(d, self.v.VisitStruct(d), func() bool {
+			return self.fields(d.TypeParams, FieldTypeParam) && self.fields(d.Fields, FieldStruct)
+		})
+	case *Interface:
+		return self.intf(d)
+	}
+	return true
+}
+
+// method walks method m.
+func (self *walker) method(m *Method) bool {
+	return self.walk(m, self.v.VisitMethod(m), func() bool {
+		if m.Receiver != nil && !self.walk(m.Receiver, self.v.VisitField(m.Receiver, FieldReceiver), nil) {
+			return false
+		}
+		return self.signature(&m.Func)
+	})
+}
+
+// intf walks interface i.
+func (self *walker) intf(i *Interface) bool {
+	return self.walk(i, self.v.VisitInterface(i), func() bool {
+		if !self.fields(i.TypeParams, FieldTypeParam) {
+			return false
+		}
+		for _, m := range i.Methods.Values() {
+			if !self.method(m) {
+				return false
+			}
+		}
+		for _, embedded := range i.Interfaces.Values() {
+			if !self.intf(embedded) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// signature walks type parameters, params and results of f.
+func (self *walker) signature(f *Func) bool {
+	return self.fields(f.TypeParams, FieldTypeParam) &&
+		self.fields(f.Params, FieldParam) &&
+		self.fields(f.Results, FieldResult)
+}
+
+// fields walks fields in role.
+func (self *walker) fields(fields *FieldMap, role FieldRole) bool {
+	if fields == nil {
+		return true
+	}
+	for _, field := range fields.Values() {
+		if !self.walk(field, self.v.VisitField(field, role), nil) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package bast
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// traceVisitor records visited elements.
+type traceVisitor struct {
+	BaseVisitor
+	trace []string
+	// skip is the kind of element whose children are skipped.
+	skip string
+	// stop is the name of the element that stops the walk.
+	stop string
+}
+
+func (self *traceVisitor) visit(kind, name string) WalkAction {
+	self.trace = append(self.trace, kind+" "+name)
+	switch {
+	case self.stop != "" && name == self.stop:
+		return WalkStop
+	case kind == self.skip:
+		return WalkSkip
+	}
+	return WalkContinue
+}
+
+func (self *traceVisitor) VisitPackage(p *Package) WalkAction { return self.visit("package", p.Name) }
+func (self *traceVisitor) VisitFunc(f *Func) WalkAction       { return self.visit("func", f.Name) }
+func (self *traceVisitor) VisitMethod(m *Method) WalkAction   { return self.visit("method", m.Name) }
+func (self *traceVisitor) VisitType(t *Type) WalkAction       { return self.visit("type", t.Name) }
+func (self *traceVisitor) VisitStruct(s *Struct) WalkAction   { return self.visit("struct", s.Name) }
+func (self *traceVisitor) VisitInterface(i *Interface) WalkAction {
+	return self.visit("interface", i.Name)
+}
+func (self *traceVisitor) VisitField(f *Field, role FieldRole) WalkAction {
+	return self.visit(fmt.Sprintf("field%d", role), f.Name)
+}
+func (self *traceVisitor) Leave(element any) WalkAction {
+	if s, ok := element.(*Struct); ok {
+		self.trace = append(self.trace, "leave "+s.Name)
+	}
+	return WalkContinue
+}
+
+// TestWalk tests walking the model with a Visitor
+func TestWalk(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	contains := func(trace []string, expected ...string) bool {
+		var i int
+		for _, entry := range trace {
+			if i < len(expected) && entry == expected[i] {
+				i++
+			}
+		}
+		return i == len(expected)
+	}
+
+	t.Run("Order", func(t *testing.T) {
+		v := &traceVisitor{}
+		if !Walk(bast, v) {
+			t.Fatal("Expected walk to complete")
+		}
+		tests := [][]string{
+			{"package handlers", "struct User", "field0 Name", "leave User"},
+			{"interface Service", "method GetUser", "field3 ctx", "field3 req", "field4 unnamed0", "field4 unnamed1"},
+			{"method GetUser", "field2 self", "field3 ctx"},
+			{"interface Repository", "method Find", "field3 id", "interface Keyed"},
+			{"struct Set", "field1 T", "field0 Items", "leave Set"},
+			{"package deps", "package handlers"},
+		}
+		for _, expected := range tests {
+			if !contains(v.trace, expected...) {
+				t.Errorf("Expected trace to contain %v in order:\n%s", expected, strings.Join(v.trace, "\n"))
+			}
+		}
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		v := &traceVisitor{skip: "struct"}
+		Walk(bast, v)
+		if !slices.Contains(v.trace, "struct User") {
+			t.Error("Expected to visit skipped struct")
+		}
+		for _, entry := range v.trace {
+			if strings.HasPrefix(entry, "field0 ") || strings.HasPrefix(entry, "leave ") {
+				t.Errorf("Expected struct children to be skipped, got %s", entry)
+			}
+		}
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		v := &traceVisitor{stop: "ServeIndex"}
+		if Walk(bast, v) {
+			t.Error("Expected walk to be stopped")
+		}
+		if last := v.trace[len(v.trace)-1]; last != "func ServeIndex" {
+			t.Errorf("Expected walk to stop at ServeIndex, stopped at %s", last)
+		}
+	})
+}
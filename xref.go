@@ -0,0 +1,247 @@
+// Copyright 2023 Vedran Vuk. All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package bast
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// RefKind specifies the kind of a declaration [Reference].
+type RefKind int
+
+const (
+	// RefValue is a use of a declaration as a value, e.g. reading a
+	// variable or a func value.
+	RefValue RefKind = iota
+	// RefCall is a call of a func or method.
+	RefCall
+	// RefType is a reference to a type, e.g. in a declaration or a
+	// conversion.
+	RefType
+	// RefField is an access of a struct field by selector or by key in a
+	// composite literal.
+	RefField
+	// RefCompositeLit is a reference to a type in a composite literal.
+	RefCompositeLit
+)
+
+// String implements fmt.Stringer.
+func (self RefKind) String() string {
+	switch self {
+	case RefValue:
+		return "value"
+	case RefCall:
+		return "call"
+	case RefType:
+		return "type"
+	case RefField:
+		return "field"
+	case RefCompositeLit:
+		return "composite literal"
+	}
+	return "unknown"
+}
+
+// Reference is a use site of a declaration.
+type Reference struct {
+	// Pos is the position of the referencing identifier.
+	Pos token.Position
+	// Kind is the kind of reference.
+	Kind RefKind
+	// Decl is the top-level declaration enclosing the reference or nil
+	// if it could not be determined.
+	Decl Declaration
+}
+
+// Referrer is a declaration that references another declaration.
+type Referrer struct {
+	// Decl is the referring declaration.
+	Decl Declaration
+	// References are the references from Decl in order of appearance.
+	References []*Reference
+}
+
+// References returns all use sites of decl across all loaded packages in
+// load order or nil if none found.
+//
+// decl may be any top-level declaration, a method, an interface method or
+// a field of a top-level struct.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Bast) References(decl Declaration) []*Reference {
+	var obj = objectOf(decl)
+	if obj == nil {
+		return nil
+	}
+	return self.xrefIndex()[obj]
+}
+
+// Referrers returns the declarations referencing decl, with references
+// grouped by referrer in order of first reference.
+//
+// References whose enclosing declaration could not be determined are
+// grouped under a Referrer with a nil Decl.
+//
+// This method requires Config.TypeChecking to be enabled.
+func (self *Bast) Referrers(decl Declaration) (out []*Referrer) {
+	var idx = make(map[Declaration]*Referrer)
+	for _, ref := range self.References(decl) {
+		var r, ok = idx[ref.Decl]
+		if !ok {
+			r = &Referrer{Decl: ref.Decl}
+			idx[ref.Decl] = r
+			out = append(out, r)
+		}
+		r.References = append(r.References, ref)
+	}
+	return
+}
+
+// xrefIndex returns the cross-reference index of all loaded packages,
+// building it on first use.
+func (self *Bast) xrefIndex() map[types.Object][]*Reference {
+	self.xrefsOnce.Do(func() {
+		self.xrefs = make(map[types.Object][]*Reference)
+		for _, pkg := range self.packages.Values() {
+			self.indexPackageRefs(pkg)
+		}
+	})
+	return self.xrefs
+}
+
+// indexPackageRefs adds references from all files of pkg to the index.
+func (self *Bast) indexPackageRefs(pkg *Package) {
+
+	if pkg.pkg == nil || pkg.pkg.TypesInfo == nil {
+		return
+	}
+	var info = pkg.pkg.TypesInfo
+
+	for _, file := range pkg.ownSyntax() {
+		for _, d := range file.Decls {
+			walkTopLevel(info, d, func(node ast.Node, defined types.Object) {
+				var decl = self.declOf(defined)
+				walkRefs(info, node, func(id *ast.Ident, obj types.Object, kind RefKind) {
+					self.xrefs[obj] = append(self.xrefs[obj], &Reference{
+						Pos:  pkg.pkg.Fset.Position(id.Pos()),
+						Kind: kind,
+						Decl: decl,
+					})
+				})
+			})
+		}
+	}
+}
+
+// walkTopLevel calls f for each node of top-level declaration d that
+// declares a top-level object, with the defined object.
+//
+// For value specs declaring multiple names the first name is used.
+func walkTopLevel(info *types.Info, d ast.Decl, f func(node ast.Node, defined types.Object)) {
+	switch n := d.(type) {
+	case *ast.FuncDecl:
+		f(n, info.Defs[n.Name])
+	case *ast.GenDecl:
+		for _, spec := range n.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				f(s, info.Defs[s.Name])
+			case *ast.ValueSpec:
+				f(s, info.Defs[s.Names[0]])
+			}
+		}
+	}
+}
+
+// walkRefs calls f for each identifier in node that uses an object that
+// may be a bast declaration, along with the generic origin of the object
+// and the kind of use.
+func walkRefs(info *types.Info, node ast.Node, f func(id *ast.Ident, obj types.Object, kind RefKind)) {
+	var stack []ast.Node
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		if id, ok := n.(*ast.Ident); ok {
+			if obj := originOf(info.Uses[id]); isDeclObject(obj) {
+				f(id, obj, refKind(id, obj, stack))
+			}
+		}
+		stack = append(stack, n)
+		return true
+	})
+}
+
+// isDeclObject returns true if obj is a package-level object, a method or a
+// struct field.
+func isDeclObject(obj types.Object) bool {
+	if obj == nil || obj.Pkg() == nil {
+		return false
+	}
+	if obj.Parent() == obj.Pkg().Scope() {
+		return true
+	}
+	switch o := obj.(type) {
+	case *types.Func:
+		return o.Signature().Recv() != nil
+	case *types.Var:
+		return o.IsField()
+	}
+	return false
+}
+
+// refKind returns the kind of reference to obj by identifier id whose
+// ancestors are stack, from outermost to innermost.
+func refKind(id *ast.Ident, obj types.Object, stack []ast.Node) RefKind {
+
+	// Climb from the identifier to the outermost expression naming obj,
+	// through qualifying selectors and type arguments.
+	var (
+		expr   ast.Node = id
+		parent ast.Node
+	)
+climb:
+	for i := len(stack) - 1; i >= 0; i-- {
+		parent = stack[i]
+		switch p := parent.(type) {
+		case *ast.SelectorExpr:
+			if p.Sel != expr {
+				break climb
+			}
+			if v, ok := obj.(*types.Var); ok && v.IsField() {
+				return RefField
+			}
+		case *ast.IndexExpr:
+			if p.X != expr {
+				break climb
+			}
+		case *ast.IndexListExpr:
+			if p.X != expr {
+				break climb
+			}
+		case *ast.ParenExpr:
+		default:
+			break climb
+		}
+		expr, parent = parent, nil
+	}
+
+	if _, isType := obj.(*types.TypeName); isType {
+		if lit, ok := parent.(*ast.CompositeLit); ok && lit.Type == expr {
+			return RefCompositeLit
+		}
+		return RefType
+	}
+	if call, ok := parent.(*ast.CallExpr); ok && call.Fun == expr {
+		return RefCall
+	}
+	if v, ok := obj.(*types.Var); ok && v.IsField() {
+		return RefField
+	}
+	return RefValue
+}
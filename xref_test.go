@@ -0,0 +1,115 @@
+package bast
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestReferences tests the cross-reference index
+func TestReferences(t *testing.T) {
+	bast := loadTestProject(t, false)
+
+	const (
+		typesPath    = "github.com/vedranvuk/bast/_testproject/pkg/types"
+		modelsPath   = "github.com/vedranvuk/bast/_testproject/pkg/models"
+		crosspkgPath = "github.com/vedranvuk/bast/_testproject/pkg/crosspkg"
+		genericsPath = "github.com/vedranvuk/bast/_testproject/pkg/generics"
+	)
+
+	t.Run("TypeReferences", func(t *testing.T) {
+		id := bast.PkgType(typesPath, "ID")
+		if id == nil {
+			t.Fatal("Expected to find types.ID")
+		}
+		refs := bast.References(id)
+		if len(refs) == 0 {
+			t.Fatal("Expected references to types.ID")
+		}
+		for _, ref := range refs {
+			if ref.Kind != RefType {
+				t.Errorf("Expected type reference at %s, got %s", ref.Pos, ref.Kind)
+			}
+		}
+
+		var processID *Referrer
+		for _, r := range bast.Referrers(id) {
+			if r.Decl == bast.PkgFunc(crosspkgPath, "ProcessID") {
+				processID = r
+			}
+		}
+		if processID == nil {
+			t.Fatal("Expected ProcessID to refer to types.ID")
+		}
+		if len(processID.References) != 2 {
+			t.Errorf("Expected 2 references from ProcessID, got %d", len(processID.References))
+		}
+		if filepath.Base(processID.References[0].Pos.Filename) != "crosspkg.go" {
+			t.Errorf("Unexpected reference position %s", processID.References[0].Pos)
+		}
+	})
+
+	t.Run("CompositeLiteralReferences", func(t *testing.T) {
+		s := bast.PkgStruct(modelsPath, "TestStruct2")
+		if s == nil {
+			t.Fatal("Expected to find TestStruct2")
+		}
+		main := bast.PkgVar("github.com/vedranvuk/bast/_testproject/cmd/main", "s")
+		found := false
+		for _, ref := range bast.References(s) {
+			if ref.Decl == main {
+				found = true
+				if ref.Kind != RefCompositeLit {
+					t.Errorf("Expected composite literal reference from main, got %s", ref.Kind)
+				}
+			}
+		}
+		if !found {
+			t.Error("Expected reference to TestStruct2 from main")
+		}
+	})
+
+	t.Run("FieldReferences", func(t *testing.T) {
+		field, ok := bast.PkgStruct(modelsPath, "TestStruct2").Fields.Get("FooField")
+		if !ok {
+			t.Fatal("Expected to find FooField")
+		}
+		referrers := map[Declaration]int{}
+		for _, ref := range bast.References(field) {
+			if ref.Kind != RefField {
+				t.Errorf("Expected field reference at %s, got %s", ref.Pos, ref.Kind)
+			}
+			referrers[ref.Decl]++
+		}
+		if referrers[bast.PkgVar(crosspkgPath, "TestStruct")] != 1 {
+			t.Error("Expected field reference from TestStruct composite literal")
+		}
+		if referrers[bast.PkgFunc(crosspkgPath, "init")] != 2 {
+			t.Errorf("Expected 2 field references from init, got %d", referrers[bast.PkgFunc(crosspkgPath, "init")])
+		}
+	})
+
+	t.Run("MethodCalls", func(t *testing.T) {
+		var find *Method
+		for _, m := range bast.PkgMethods(genericsPath) {
+			if m.Name == "Find" {
+				find = m
+			}
+		}
+		if find == nil {
+			t.Fatal("Expected to find Node.Find")
+		}
+		refs := bast.References(find)
+		if len(refs) != 1 {
+			t.Fatalf("Expected 1 reference to Node.Find, got %d", len(refs))
+		}
+		if refs[0].Kind != RefCall || refs[0].Decl != find {
+			t.Errorf("Expected recursive call from Node.Find, got %s", refs[0].Kind)
+		}
+	})
+
+	t.Run("Unreferenced", func(t *testing.T) {
+		if refs := bast.References(bast.PkgFunc(crosspkgPath, "ProcessID")); len(refs) != 0 {
+			t.Errorf("Expected no references to ProcessID, got %d", len(refs))
+		}
+	})
+}